@@ -6,8 +6,10 @@ import (
 	"net/http"
 	"os"
 	"pr-manage-service/internal/application/usecases"
+	"pr-manage-service/internal/domain"
 	"pr-manage-service/internal/interfaces/handlers"
 	"pr-manage-service/internal/transport/repository"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -29,7 +31,13 @@ var (
 	DSN  string
 	MODE Mode = Debug //default
 
-	ADMIN_TOKEN string
+	ADMIN_TOKEN  string
+	ACTOR_SECRET string // ключ подписи Actor-Signature; не задан - участники не аутентифицируются
+
+	REVIEWER_STRATEGY domain.ReviewerStrategy = domain.LeastLoaded //default
+	REVIEWER_SEED     int64                   = time.Now().UnixNano()
+
+	AUTO_HANDOVER_INTERVAL = time.Minute //default; 0 - автоматическая передача ревью выключена
 )
 
 func init() {
@@ -51,6 +59,30 @@ func init() {
 			log.Println("\033[33m(ENV)\033[39m ADMIN_TOKEN:\033[32m", AdminToken, "\033[39m")
 		}
 	}
+	if secret := os.Getenv("ACTOR_SECRET"); secret != "" {
+		ACTOR_SECRET = secret
+	}
+	if strategy := os.Getenv("REVIEWER_STRATEGY"); strategy != "" {
+		if s, ok := domain.ParseReviewerStrategy(strings.ToLower(strategy)); ok {
+			REVIEWER_STRATEGY = s
+		} else {
+			log.Fatal("(ENV) unknown REVIEWER_STRATEGY: ", strategy)
+		}
+	}
+	if seed := os.Getenv("REVIEWER_SEED"); seed != "" {
+		if v, err := strconv.ParseInt(seed, 10, 64); err == nil {
+			REVIEWER_SEED = v
+		} else {
+			log.Fatal("(ENV) REVIEWER_SEED must be an integer: ", seed)
+		}
+	}
+	if interval := os.Getenv("AUTO_HANDOVER_INTERVAL"); interval != "" {
+		if v, err := time.ParseDuration(interval); err == nil && v >= 0 {
+			AUTO_HANDOVER_INTERVAL = v
+		} else {
+			log.Fatal("(ENV) AUTO_HANDOVER_INTERVAL must be a non-negative duration: ", interval)
+		}
+	}
 	switch MODE {
 	case Debug:
 		gin.SetMode(gin.DebugMode)
@@ -78,39 +110,70 @@ func main() {
 	}
 	poolCancel()
 
+	selectors := domain.NewReviewerSelectors(REVIEWER_STRATEGY, REVIEWER_SEED)
+	auth := handlers.Auth{AdminToken: ADMIN_TOKEN, ActorSecret: ACTOR_SECRET}
+
 	// team depends
 	teamRepository := repository.NewTeamRepository(ctx, pool, 2*time.Second)
-	teamUseCase := usecases.NewTeamUseCase(teamRepository)
-	teamHandler := handlers.NewTeamHandler(teamUseCase)
+	teamUseCase := usecases.NewTeamUseCase(teamRepository, selectors)
+	teamHandler := handlers.NewTeamHandler(teamUseCase, auth)
 
 	// pr depends
 	prRepository := repository.NewPullRequestRepository(ctx, pool, 10*time.Second)
-	prUseCase := usecases.NewPrUseCase(prRepository)
-	prHandler := handlers.NewPRHandler(ctx, prUseCase)
+	prUseCase := usecases.NewPrUseCase(prRepository, selectors)
+	prHandler := handlers.NewPRHandler(ctx, prUseCase, auth)
 
 	// user depends
 	userRepository := repository.NewUserRepository(ctx, pool, 2*time.Second)
-	userUseCase := usecases.NewUserUseCase(userRepository, prUseCase)
-	userHandler := handlers.NewUserHandler(userUseCase, ADMIN_TOKEN)
+	userUseCase := usecases.NewUserUseCase(userRepository, prUseCase, selectors)
+	userHandler := handlers.NewUserHandler(userUseCase, auth)
+
+	// stats depends
+	statsRepository := repository.NewStatsRepository(ctx, pool, 5*time.Second)
+	statsUseCase := usecases.NewStatsUseCase(statsRepository)
+	statsHandler := handlers.NewStatsHandler(statsUseCase)
 
 	r := gin.Default()
 	teamApi := r.Group("/team")
 	{
 		teamApi.POST("/add", teamHandler.AddTeamHandler)
 		teamApi.GET("/get", teamHandler.GetTeamHandler)
+		teamApi.GET("/list", teamHandler.ListTeamsHandler)
+		teamApi.GET("/settings", teamHandler.GetSettingsHandler)
+		teamApi.POST("/settings", teamHandler.UpdateSettingsHandler)
+		teamApi.POST("/members/add", teamHandler.AddMembersHandler)
+		teamApi.POST("/members/remove", teamHandler.RemoveMemberHandler)
+		teamApi.POST("/members/rename", teamHandler.RenameMemberHandler)
+		teamApi.DELETE("", teamHandler.DeleteTeamHandler)
 	}
 	userApi := r.Group("/users")
 	{
 		userApi.POST("/setIsActive", userHandler.SetIsActiveHandler)
 		userApi.GET("/getReview", userHandler.GetReviewHandler)
+		userApi.POST("/bulkDeactivate", userHandler.BulkDeactivateHandler)
+		userApi.POST("/transfer", userHandler.TransferHandler)
+		userApi.POST("/handover", userHandler.HandoverHandler)
+		userApi.POST("/availability", userHandler.AddAvailabilityHandler)
+		userApi.GET("/availability", userHandler.ListAvailabilityHandler)
+		userApi.POST("/availability/update", userHandler.UpdateAvailabilityHandler)
+		userApi.DELETE("/availability", userHandler.DeleteAvailabilityHandler)
 	}
 	prApi := r.Group("/pullRequest")
 	{
 		prApi.POST("create", prHandler.CreateHandler)
 		prApi.POST("/merge", prHandler.MergeHandler)
 		prApi.POST("/reassign", prHandler.ReassignHandler)
+		prApi.POST("/review", prHandler.ReviewHandler)
+		prApi.POST("/close", prHandler.CloseHandler)
+		prApi.POST("/reopen", prHandler.ReopenHandler)
+		prApi.POST("/ready", prHandler.ReadyHandler)
+		prApi.GET("/get", prHandler.GetHandler)
+		prApi.GET("/list", prHandler.ListHandler)
+		prApi.GET("/history", prHandler.HistoryHandler)
 	}
 
+	r.GET("/stats", statsHandler.GetStatsHandler)
+
 	server := &http.Server{
 		Addr:    ":8080",
 		Handler: r,
@@ -127,6 +190,26 @@ func main() {
 	}()
 	println("(server) listen http connections on ", server.Addr)
 
+	// Передаём OPEN ревью пользователей, чей период недоступности с auto_handover начался
+	if AUTO_HANDOVER_INTERVAL > 0 {
+		go func() {
+			ticker := time.NewTicker(AUTO_HANDOVER_INTERVAL)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if handedOver, err := userUseCase.AutoHandover(); err != nil {
+						logrus.Error("(auto handover) error: ", err.Error())
+					} else if handedOver > 0 {
+						logrus.Info("(auto handover) periods started: ", handedOver)
+					}
+				}
+			}
+		}()
+	}
+
 	wg.Wait()
 	go func() {
 		shdCtx, shdCencel := context.WithTimeout(ctx, 3*time.Second)
@@ -0,0 +1,141 @@
+package usecases
+
+import (
+	"pr-manage-service/internal/domain"
+	"pr-manage-service/internal/interfaces/dto"
+	"pr-manage-service/pkg/cursor"
+	"pr-manage-service/pkg/errs"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 100
+)
+
+// prCursor - содержимое курсора страницы PR; порядок сортировки сохраняется,
+// чтобы курсор нельзя было применить к выборке с другой сортировкой
+type prCursor struct {
+	Sort domain.PRSort `json:"s"`
+	Time time.Time     `json:"t"`
+	PrID string        `json:"id"`
+}
+
+// List implements domain.PRService.
+func (p *prUseCase) List(req *dto.PRListRequest) (*dto.PRListResponse, error) {
+	filter := domain.PRListFilter{
+		TeamName:   req.TeamName,
+		AuthorID:   req.AuthorID,
+		ReviewerID: req.ReviewerID,
+	}
+	var err error
+	if filter.Status, err = parseStatus(req.Status); err != nil {
+		return nil, err
+	}
+	if req.NeedMoreReviewers != "" {
+		needMore, err := strconv.ParseBool(req.NeedMoreReviewers)
+		if err != nil {
+			return nil, &errs.InvalidError{Domain: "'need_more_reviewers'", Desc: "expected true or false"}
+		}
+		filter.NeedMoreReviewers = &needMore
+	}
+	if filter.CreatedFrom, err = parseTime("created_from", req.CreatedFrom); err != nil {
+		return nil, err
+	}
+	if filter.CreatedTo, err = parseTime("created_to", req.CreatedTo); err != nil {
+		return nil, err
+	}
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && !filter.CreatedFrom.Before(*filter.CreatedTo) {
+		return nil, &errs.InvalidError{Domain: "pull requests", Desc: "'created_from' must be before 'created_to'"}
+	}
+	sort, ok := domain.ParsePRSort(req.Sort)
+	if !ok {
+		return nil, &errs.InvalidError{
+			Domain: "'sort'",
+			Desc:   "expected created_at_desc, created_at_asc, updated_at_desc or updated_at_asc",
+		}
+	}
+	filter.Sort = sort
+	if filter.After, err = decodePRCursor(req.Cursor, sort); err != nil {
+		return nil, err
+	}
+	limit, err := parseLimit(req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	// Лишняя запись показывает, есть ли следующая страница
+	filter.Limit = limit + 1
+
+	items, err := p.repo.List(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.PRListResponse{PullRequests: make([]dto.PRListItem, 0, limit)}
+	if len(items) > limit {
+		items = items[:limit]
+		resp.NextCursor = encodePRCursor(sort, &items[limit-1].PullRequest)
+	}
+	for _, item := range items {
+		reviewers := item.Reviewers
+		if reviewers == nil {
+			reviewers = []string{}
+		}
+		resp.PullRequests = append(resp.PullRequests, dto.PRListItem{
+			PullRequestID:     item.PrID,
+			PullRequestName:   item.PrName,
+			AuthorID:          item.AuthorID,
+			TeamName:          item.TeamName,
+			Status:            string(item.Status),
+			NeedMoreReviewers: item.NeedMoreReviewers,
+			AssignedReviewers: reviewers,
+			CreatedAt:         item.CreatedAt,
+			UpdatedAt:         item.UpdatedAt,
+			MergedAt:          item.MergedAt,
+		})
+	}
+	return resp, nil
+}
+
+func parseStatus(value string) (domain.STATUS, error) {
+	switch status := domain.STATUS(value); status {
+	case "", domain.DRAFT, domain.OPEN, domain.MERGED, domain.CLOSED:
+		return status, nil
+	}
+	return "", &errs.InvalidError{Domain: "'status'", Desc: "expected DRAFT, OPEN, MERGED or CLOSED"}
+}
+
+// parseLimit разбирает размер страницы: по умолчанию defaultPageLimit, не больше maxPageLimit
+func parseLimit(value string) (int, error) {
+	if value == "" {
+		return defaultPageLimit, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > maxPageLimit {
+		return 0, &errs.InvalidError{Domain: "'limit'", Desc: "expected integer from 1 to " + strconv.Itoa(maxPageLimit)}
+	}
+	return limit, nil
+}
+
+func encodePRCursor(sort domain.PRSort, last *domain.PullRequest) string {
+	c := prCursor{Sort: sort, Time: last.CreatedAt, PrID: last.PrID}
+	if sort == domain.SortUpdatedAsc || sort == domain.SortUpdatedDesc {
+		c.Time = last.UpdatedAt
+	}
+	return cursor.Encode(c)
+}
+
+func decodePRCursor(value string, sort domain.PRSort) (*domain.PRPosition, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var c prCursor
+	if err := cursor.Decode(value, &c); err != nil || c.PrID == "" {
+		return nil, &errs.InvalidError{Domain: "'cursor'", Desc: "malformed cursor"}
+	}
+	if c.Sort != sort {
+		return nil, &errs.InvalidError{Domain: "'cursor'", Desc: "cursor was issued for sort " + string(c.Sort)}
+	}
+	return &domain.PRPosition{Time: c.Time, PrID: c.PrID}, nil
+}
@@ -3,37 +3,61 @@ package usecases
 import (
 	"pr-manage-service/internal/domain"
 	"pr-manage-service/internal/interfaces/dto"
+	"pr-manage-service/pkg/errs"
 )
 
 type prUseCase struct {
-	repo domain.PRRepository
+	repo      domain.PRRepository
+	selectors *domain.ReviewerSelectors
 }
 
 // GetPRsByUser implements domain.PRService.
-func (p *prUseCase) GetPRsByUser(userID string, teamName string) (*dto.UserPRsResponse, error) {
-	if PRs, err := p.repo.GetWithUser(&domain.User{UserID: userID, TeamName: teamName}); err != nil {
+func (p *prUseCase) GetPRsByUser(req *dto.UserPRsRequest) (*dto.UserPRsResponse, error) {
+	role, ok := domain.ParsePRRole(req.Role)
+	if !ok {
+		return nil, &errs.InvalidError{Domain: "'role'", Desc: "expected reviewer, author or any"}
+	}
+	filter := domain.UserPRsFilter{Role: role}
+	var err error
+	if filter.Status, err = parseStatus(req.Status); err != nil {
 		return nil, err
-	} else {
-		prResp := make([]dto.PRResponse, 0, len(*PRs))
-		for _, pr := range *PRs {
-			prResp = append(prResp, dto.PRResponse{
-				PullRequestID:   pr.PrID,
-				PullRequestName: pr.PrName,
-				AuthorID:        pr.AuthorID,
-				TeamName:        pr.TeamName,
-				Status:          string(pr.Status),
-			})
-		}
-		return &dto.UserPRsResponse{
-			UserID:       userID,
-			PullRequests: prResp,
-		}, nil
 	}
+	if filter.After, err = decodePRCursor(req.Cursor, domain.SortCreatedDesc); err != nil {
+		return nil, err
+	}
+	limit, err := parseLimit(req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	// Лишняя запись показывает, есть ли следующая страница
+	filter.Limit = limit + 1
+
+	PRs, err := p.repo.GetWithUser(&domain.User{UserID: req.UserID, TeamName: req.TeamName}, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.UserPRsResponse{
+		UserID:       req.UserID,
+		PullRequests: make([]dto.UserPRResponse, 0, limit),
+	}
+	if len(PRs) > limit {
+		PRs = PRs[:limit]
+		resp.NextCursor = encodePRCursor(domain.SortCreatedDesc, &PRs[limit-1].PullRequest)
+	}
+	for _, pr := range PRs {
+		resp.PullRequests = append(resp.PullRequests, dto.UserPRResponse{
+			PRResponse: toPRResponse(&pr.PullRequest, pr.Reviewers),
+			Role:       string(pr.Role),
+		})
+	}
+	return resp, nil
 }
 
-func NewPrUseCase(repo domain.PRRepository) domain.PRService {
+func NewPrUseCase(repo domain.PRRepository, selectors *domain.ReviewerSelectors) domain.PRService {
 	return &prUseCase{
-		repo: repo,
+		repo:      repo,
+		selectors: selectors,
 	}
 }
 
@@ -45,53 +69,203 @@ func (p *prUseCase) Create(req *dto.PRCreateRequest) (*dto.PRResponse, error) {
 		AuthorID: req.AuthorID,
 		TeamName: req.TeamName,
 	}
-	if assigned_revs, err := p.repo.CreateNewPR(pr); err != nil {
+	if req.Draft {
+		pr.Status = domain.DRAFT
+	}
+	if states, err := p.repo.CreateNewPR(pr, p.selectors); err != nil {
 		return nil, err
 	} else {
-		return &dto.PRResponse{
-			PullRequestID:     req.PullRequestID,
-			PullRequestName:   req.PullRequestName,
-			AuthorID:          req.AuthorID,
-			TeamName:          req.TeamName,
-			Status:            string(pr.Status),
-			AssignedReviewers: assigned_revs,
-		}, nil
+		resp := toPRResponse(pr, states)
+		return &resp, nil
 	}
 }
 
 // Merge implements domain.PRService.
-func (p *prUseCase) Merge(req *dto.PRCreateRequest) (*dto.PRMergeResponse, error) {
-	if pr, ar, err := p.repo.Merge(req.PullRequestID); err != nil {
+func (p *prUseCase) Merge(req *dto.PRCreateRequest, actor domain.Actor) (*dto.PRMergeResponse, error) {
+	if pr, states, err := p.repo.Merge(req.PullRequestID, actor); err != nil {
 		return nil, err
 	} else {
+		resp := toPRResponse(pr, states)
 		return &dto.PRMergeResponse{
-			PRResponse: &dto.PRResponse{
-				PullRequestID:     req.PullRequestID,
-				PullRequestName:   pr.PrName,
-				TeamName:          pr.TeamName,
-				Status:            string(pr.Status),
-				AssignedReviewers: ar,
-			},
-			MergedAt: pr.UpdatedAt,
+			PRResponse: &resp,
+			MergedAt:   *pr.MergedAt,
 		}, nil
 	}
 }
 
 // Reassign implements domain.PRService.
-func (p *prUseCase) Reassign(prID string, oldRevID string) (*dto.PRReassignResponse, error) {
-	if resp, revs, replacedUserID, err := p.repo.Reassign(prID, oldRevID); err != nil {
+func (p *prUseCase) Reassign(req *dto.PRReassignRequest, actor domain.Actor) (*dto.PRReassignResponse, error) {
+	if req.NewReviewerID != "" && req.NewReviewerID == req.OldReviewerID {
+		return nil, &errs.InvalidError{Domain: "new_reviewer_id", Desc: "must differ from old_reviewer_id"}
+	}
+	if len(req.Reason) > 500 {
+		return nil, &errs.InvalidError{Domain: "reason", Desc: "too long (max 500 characters)"}
+	}
+	if resp, states, replacedUserID, err := p.repo.Reassign(req.PullRequestID, req.OldReviewerID, req.NewReviewerID, req.Reason, actor, p.selectors); err != nil {
 		return nil, err
 	} else {
 		return &dto.PRReassignResponse{
-			PR: dto.PRResponse{
-				PullRequestID:     resp.PrID,
-				PullRequestName:   resp.PrName,
-				AuthorID:          resp.AuthorID,
-				TeamName:          resp.TeamName,
-				Status:            string(resp.Status),
-				AssignedReviewers: revs,
-			},
+			PR:         toPRResponse(resp, states),
 			ReplacedBy: replacedUserID,
 		}, nil
 	}
 }
+
+func toReassignmentResponses(reassignments []domain.Reassignment) []dto.ReassignmentResponse {
+	if len(reassignments) == 0 {
+		return nil
+	}
+	resp := make([]dto.ReassignmentResponse, len(reassignments))
+	for index, r := range reassignments {
+		resp[index] = dto.ReassignmentResponse{
+			PullRequestID:     r.PrID,
+			OldReviewerID:     r.OldReviewerID,
+			NewReviewerID:     r.NewReviewerID,
+			NeedMoreReviewers: r.NeedMoreReviewers,
+		}
+	}
+	return resp
+}
+
+// Review implements domain.PRService.
+func (p *prUseCase) Review(req *dto.PRReviewRequest, actor domain.Actor) (*dto.PRResponse, error) {
+	// Решение отправляет сам ревьювер; иначе любой мог бы одобрить PR за него и пройти merge-политику
+	if !actor.Admin && !actor.Is(req.ReviewerID) {
+		return nil, &errs.ForbiddenError{Desc: "only the reviewer can submit their review"}
+	}
+	state := domain.ReviewState(req.State)
+	switch state {
+	case domain.APPROVED, domain.CHANGES_REQUESTED, domain.COMMENTED:
+	default:
+		return nil, &errs.InvalidError{
+			Domain: "state",
+			Desc:   "expected APPROVED, CHANGES_REQUESTED or COMMENTED",
+		}
+	}
+	if len(req.Body) > 10000 {
+		return nil, &errs.InvalidError{Domain: "body", Desc: "too long (max 10000 characters)"}
+	}
+
+	pr, states, err := p.repo.SubmitReview(req.PullRequestID, req.ReviewerID, state, req.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp := toPRResponse(pr, states)
+	return &resp, nil
+}
+
+// toPRResponse собирает ответ по PR: назначенные ревьюверы и последнее решение каждого из них
+func toPRResponse(pr *domain.PullRequest, states []domain.ReviewerState) dto.PRResponse {
+	resp := dto.PRResponse{
+		PullRequestID:   pr.PrID,
+		PullRequestName: pr.PrName,
+		AuthorID:        pr.AuthorID,
+		TeamName:        pr.TeamName,
+		Status:          string(pr.Status),
+		ReviewerStates:  toReviewerStateResponses(states),
+	}
+	for _, s := range states {
+		resp.AssignedReviewers = append(resp.AssignedReviewers, s.UserID)
+	}
+	return resp
+}
+
+func toReviewerStateResponses(states []domain.ReviewerState) []dto.ReviewerStateResponse {
+	if len(states) == 0 {
+		return nil
+	}
+	resp := make([]dto.ReviewerStateResponse, len(states))
+	for index, s := range states {
+		resp[index] = dto.ReviewerStateResponse{
+			ReviewerID:  s.UserID,
+			State:       string(s.State),
+			SubmittedAt: s.SubmittedAt,
+		}
+	}
+	return resp
+}
+
+// Close implements domain.PRService.
+func (p *prUseCase) Close(prID string, actor domain.Actor) (*dto.PRResponse, error) {
+	pr, states, err := p.repo.Close(prID, actor)
+	if err != nil {
+		return nil, err
+	}
+	resp := toPRResponse(pr, states)
+	return &resp, nil
+}
+
+// Reopen implements domain.PRService.
+func (p *prUseCase) Reopen(prID string, actor domain.Actor) (*dto.PRReopenResponse, error) {
+	pr, states, reassignments, err := p.repo.Reopen(prID, actor, p.selectors)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.PRReopenResponse{
+		PR:            toPRResponse(pr, states),
+		Reassignments: toReassignmentResponses(reassignments),
+	}, nil
+}
+
+// Ready implements domain.PRService.
+func (p *prUseCase) Ready(prID string, actor domain.Actor) (*dto.PRResponse, error) {
+	pr, states, err := p.repo.Ready(prID, actor, p.selectors)
+	if err != nil {
+		return nil, err
+	}
+	resp := toPRResponse(pr, states)
+	return &resp, nil
+}
+
+// Get implements domain.PRService.
+func (p *prUseCase) Get(prID string) (*dto.PRDetailsResponse, error) {
+	pr, states, err := p.repo.Get(prID)
+	if err != nil {
+		return nil, err
+	}
+	reviewers := make([]dto.PRReviewerResponse, len(states))
+	for index, s := range states {
+		reviewers[index] = dto.PRReviewerResponse{
+			ReviewerID:  s.UserID,
+			AssignedAt:  s.AssignedAt,
+			State:       string(s.State),
+			SubmittedAt: s.SubmittedAt,
+		}
+	}
+	return &dto.PRDetailsResponse{
+		PullRequestID:     pr.PrID,
+		PullRequestName:   pr.PrName,
+		AuthorID:          pr.AuthorID,
+		TeamName:          pr.TeamName,
+		Status:            string(pr.Status),
+		NeedMoreReviewers: pr.NeedMoreReviewers,
+		CreatedAt:         pr.CreatedAt,
+		UpdatedAt:         pr.UpdatedAt,
+		MergedAt:          pr.MergedAt,
+		Reviewers:         reviewers,
+	}, nil
+}
+
+// History implements domain.PRService.
+func (p *prUseCase) History(prID string) (*dto.PRHistoryResponse, error) {
+	events, err := p.repo.History(prID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dto.PRHistoryResponse{
+		PullRequestID: prID,
+		Events:        make([]dto.PREventResponse, len(events)),
+	}
+	for index, e := range events {
+		resp.Events[index] = dto.PREventResponse{
+			Type:          string(e.Type),
+			ActorID:       e.ActorID,
+			ReviewerID:    e.ReviewerID,
+			NewReviewerID: e.NewReviewerID,
+			Details:       e.Details,
+			Reason:        e.Reason,
+			CreatedAt:     e.CreatedAt,
+		}
+	}
+	return resp, nil
+}
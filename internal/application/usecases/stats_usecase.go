@@ -0,0 +1,77 @@
+package usecases
+
+import (
+	"pr-manage-service/internal/domain"
+	"pr-manage-service/internal/interfaces/dto"
+	"pr-manage-service/pkg/errs"
+	"time"
+)
+
+type statsUseCase struct {
+	repo domain.StatsRepository
+}
+
+func NewStatsUseCase(repo domain.StatsRepository) domain.StatsService {
+	return &statsUseCase{
+		repo: repo,
+	}
+}
+
+// GetStats implements domain.StatsService.
+func (s *statsUseCase) GetStats(teamName string, from string, to string) (*dto.StatsResponse, error) {
+	filter := domain.StatsFilter{TeamName: teamName}
+	var err error
+	if filter.From, err = parseTime("from", from); err != nil {
+		return nil, err
+	}
+	if filter.To, err = parseTime("to", to); err != nil {
+		return nil, err
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, &errs.InvalidError{Domain: "stats", Desc: "'from' must be before 'to'"}
+	}
+
+	stats, err := s.repo.GetStats(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.StatsResponse{
+		ReviewAssignments: toUserStats(stats.ReviewAssignments),
+		TeamPRs:           make([]dto.TeamPRStats, len(stats.TeamPRs)),
+		AuthorPRs:         toUserStats(stats.AuthorPRs),
+		NeedMoreReviewers: stats.NeedMoreReviewers,
+	}
+	for index, team := range stats.TeamPRs {
+		resp.TeamPRs[index] = dto.TeamPRStats{
+			TeamName: team.TeamName,
+			Open:     team.Open,
+			Merged:   team.Merged,
+		}
+	}
+	return resp, nil
+}
+
+func toUserStats(counts []domain.UserCount) []dto.UserStats {
+	resp := make([]dto.UserStats, len(counts))
+	for index, c := range counts {
+		resp[index] = dto.UserStats{
+			UserID:   c.UserID,
+			TeamName: c.TeamName,
+			Count:    c.Count,
+		}
+	}
+	return resp
+}
+
+// parseTime разбирает необязательный параметр времени в формате RFC3339
+func parseTime(name, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, &errs.InvalidError{Domain: "'" + name + "'", Desc: "expected RFC3339 time"}
+	}
+	return &t, nil
+}
@@ -4,37 +4,185 @@ import (
 	"fmt"
 	"pr-manage-service/internal/domain"
 	"pr-manage-service/internal/interfaces/dto"
+	"pr-manage-service/pkg/cursor"
 	"pr-manage-service/pkg/errs"
 	"strings"
 )
 
 type teamUseCase struct {
-	repo domain.TeamRepository
+	repo      domain.TeamRepository
+	selectors *domain.ReviewerSelectors
 }
 
-func NewTeamUseCase(repo domain.TeamRepository) domain.TeamService {
+func NewTeamUseCase(repo domain.TeamRepository, selectors *domain.ReviewerSelectors) domain.TeamService {
 	return &teamUseCase{
-		repo: repo,
+		repo:      repo,
+		selectors: selectors,
 	}
 }
 
 // AddTeam implements domain.TeamService.
-func (t *teamUseCase) AddTeam(team *dto.TeamRequest) error {
+func (t *teamUseCase) AddTeam(team *dto.TeamRequest, actor domain.Actor) error {
 	if err := validateTeam(team, false); err != nil {
 		return &errs.InvalidError{
 			Domain: "team",
 			Desc:   err.Error(),
 		}
 	}
-	Members := make([]domain.User, len(team.Members))
-	for index, member := range team.Members {
-		Members[index] = domain.User{
+	Members := toDomainMembers(team.Members)
+	// Лида ещё нет у новой команды, поэтому роли здесь раздаёт только администратор;
+	// лид существующей команды меняет их через /team/members/add
+	if domain.SetsRoles(Members) && !actor.Admin {
+		return &errs.ForbiddenError{Desc: "only an admin can set role or mandatory_reviewer in /team/add"}
+	}
+	err := t.repo.AddNewTeam(team.TeamName, &Members)
+	if _, exists := err.(*errs.AlreadyExistsError); exists {
+		// Существующая команда: создаём новых участников и обновляем остальных
+		_, err = t.repo.UpsertMembers(team.TeamName, Members, actor, t.selectors)
+	}
+	return err
+}
+
+// AddMembers implements domain.TeamService.
+func (t *teamUseCase) AddMembers(req *dto.TeamRequest, actor domain.Actor) (*dto.TeamMembersResponse, error) {
+	if err := validateTeam(req, false); err != nil {
+		return nil, &errs.InvalidError{
+			Domain: "team",
+			Desc:   err.Error(),
+		}
+	}
+	change, err := t.repo.UpsertMembers(req.TeamName, toDomainMembers(req.Members), actor, t.selectors)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TeamMembersResponse{
+		TeamName:      req.TeamName,
+		Added:         change.Added,
+		Updated:       change.Updated,
+		Reassignments: toReassignmentResponses(change.Reassignments),
+		AssignedPRs:   change.AssignedPRs,
+	}, nil
+}
+
+// RemoveMember implements domain.TeamService.
+func (t *teamUseCase) RemoveMember(req *dto.TeamMemberRequest, actor domain.Actor) (*dto.TeamMemberRemoveResponse, error) {
+	if strings.TrimSpace(req.TeamName) == "" || strings.TrimSpace(req.UserID) == "" {
+		return nil, &errs.InvalidError{Domain: "team member", Desc: "team_name and user_id are required"}
+	}
+	reassignments, err := t.repo.RemoveMember(req.TeamName, req.UserID, actor, t.selectors)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TeamMemberRemoveResponse{
+		TeamName:      req.TeamName,
+		UserID:        req.UserID,
+		Reassignments: toReassignmentResponses(reassignments),
+	}, nil
+}
+
+// RenameMember implements domain.TeamService.
+func (t *teamUseCase) RenameMember(req *dto.TeamMemberRequest, actor domain.Actor) (*dto.UserResponse, error) {
+	if strings.TrimSpace(req.TeamName) == "" {
+		return nil, &errs.InvalidError{Domain: "team member", Desc: "team_name cannot be empty"}
+	}
+	if err := validateTeamMember(dto.Member{UserID: req.UserID, UserName: req.UserName}); err != nil {
+		return nil, &errs.InvalidError{Domain: "team member", Desc: err.Error()}
+	}
+	user, err := t.repo.RenameMember(req.TeamName, req.UserID, req.UserName, actor)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.UserResponse{
+		UserRequest: &dto.UserRequest{
+			UserID:   user.UserID,
+			TeamName: user.TeamName,
+			IsActive: user.IsActive,
+		},
+		UserName: user.UserName,
+	}, nil
+}
+
+// DeleteTeam implements domain.TeamService.
+func (t *teamUseCase) DeleteTeam(teamName string, mode string, force bool) (*dto.TeamDeleteResponse, error) {
+	if strings.TrimSpace(teamName) == "" {
+		return nil, &errs.InvalidError{Domain: "team", Desc: "team_name cannot be empty"}
+	}
+	deleteMode := domain.TeamDeleteMode(mode)
+	if mode == "" {
+		deleteMode = domain.DeleteArchive
+	}
+	if deleteMode != domain.DeleteHard && deleteMode != domain.DeleteArchive {
+		return nil, &errs.InvalidError{Domain: "'mode'", Desc: "expected hard or archive"}
+	}
+	openPRs, err := t.repo.DeleteTeam(teamName, deleteMode, force)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TeamDeleteResponse{
+		TeamName: teamName,
+		Mode:     string(deleteMode),
+		OpenPRs:  openPRs,
+	}, nil
+}
+
+// teamCursor - содержимое курсора страницы /team/list
+type teamCursor struct {
+	TeamName string `json:"n"`
+}
+
+// ListTeams implements domain.TeamService.
+func (t *teamUseCase) ListTeams(prefix string, limit string, after string) (*dto.TeamListResponse, error) {
+	filter := domain.TeamListFilter{Prefix: prefix}
+	if after != "" {
+		var c teamCursor
+		if err := cursor.Decode(after, &c); err != nil || c.TeamName == "" {
+			return nil, &errs.InvalidError{Domain: "'cursor'", Desc: "malformed cursor"}
+		}
+		filter.After = c.TeamName
+	}
+	pageLimit, err := parseLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+	// Лишняя запись показывает, есть ли следующая страница
+	filter.Limit = pageLimit + 1
+
+	teams, err := t.repo.ListTeams(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.TeamListResponse{Teams: make([]dto.TeamSummary, 0, pageLimit)}
+	if len(teams) > pageLimit {
+		teams = teams[:pageLimit]
+		resp.NextCursor = cursor.Encode(teamCursor{TeamName: teams[pageLimit-1].TeamName})
+	}
+	for _, team := range teams {
+		resp.Teams = append(resp.Teams, dto.TeamSummary{
+			TeamName:      team.TeamName,
+			Members:       team.Members,
+			ActiveMembers: team.ActiveMembers,
+			OpenPRs:       team.OpenPRs,
+		})
+	}
+	return resp, nil
+}
+
+func toDomainMembers(members []dto.Member) []domain.User {
+	users := make([]domain.User, len(members))
+	for index, member := range members {
+		users[index] = domain.User{
 			UserID:   member.UserID,
+			Login:    member.Login,
 			UserName: member.UserName,
 			IsActive: member.IsActive,
+
+			Role:              domain.TeamRole(member.Role),
+			MandatoryReviewer: member.MandatoryReviewer,
+			MaxOpenReviews:    member.MaxOpenReviews,
 		}
 	}
-	return t.repo.AddNewTeam(team.TeamName, &Members)
+	return users
 }
 
 // GetTeamByName implements domain.TeamService.
@@ -45,10 +193,17 @@ func (t *teamUseCase) GetTeamByName(teamName string) (*dto.TeamResponse, error)
 	}
 	members := make([]dto.Member, len(team.Members))
 	for index, user := range team.Members {
+		openReviews := user.OpenReviews
 		members[index] = dto.Member{
 			UserID:   user.UserID,
+			Login:    user.Login,
 			UserName: user.UserName,
 			IsActive: user.IsActive,
+
+			Role:              string(user.Role),
+			MandatoryReviewer: user.MandatoryReviewer,
+			MaxOpenReviews:    user.MaxOpenReviews,
+			OpenReviews:       &openReviews,
 		}
 	}
 	return &dto.TeamResponse{
@@ -108,6 +263,25 @@ func validateTeamMember(member dto.Member) error {
 		return fmt.Errorf("member:%s : user_id too long (max 50 characters)", member.UserID)
 	}
 
+	// login необязателен: по умолчанию совпадает с user_id
+	if member.Login != "" && (strings.TrimSpace(member.Login) == "" || len(member.Login) > 50) {
+		return fmt.Errorf("member:%s : login must be non-blank and at most 50 characters", member.UserID)
+	}
+
+	// Роль необязательна: новый участник получает member, существующий сохраняет текущую
+	if _, ok := domain.ParseTeamRole(member.Role); !ok {
+		return fmt.Errorf("member:%s : unknown role '%s'", member.UserID, member.Role)
+	}
+
+	if member.MandatoryReviewer && domain.TeamRole(member.Role) != domain.RoleLead {
+		return fmt.Errorf("member:%s : mandatory_reviewer requires role 'lead'", member.UserID)
+	}
+
+	// max_open_reviews необязателен: без него лимит не меняется, 0 снимает лимит
+	if member.MaxOpenReviews != nil && *member.MaxOpenReviews < 0 {
+		return fmt.Errorf("member:%s : max_open_reviews cannot be negative", member.UserID)
+	}
+
 	// Валидация username
 	if strings.TrimSpace(member.UserName) == "" {
 		return fmt.Errorf("member:%s : username cannot be empty", member.UserID)
@@ -122,3 +296,102 @@ func validateTeamMember(member dto.Member) error {
 
 	return nil
 }
+
+// GetSettings implements domain.TeamService.
+func (t *teamUseCase) GetSettings(teamName string) (*dto.TeamSettingsResponse, error) {
+	settings, err := t.repo.GetSettings(teamName)
+	if err != nil {
+		return nil, err
+	}
+	return toSettingsResponse(settings), nil
+}
+
+// UpdateSettings implements domain.TeamService.
+func (t *teamUseCase) UpdateSettings(req *dto.TeamSettingsRequest, actor domain.Actor) (*dto.TeamSettingsResponse, error) {
+	if strings.TrimSpace(req.TeamName) == "" {
+		return nil, &errs.InvalidError{Domain: "team settings", Desc: "team_name cannot be empty"}
+	}
+	settings, err := t.repo.GetSettings(req.TeamName)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.MinReviewers != nil {
+		settings.MinReviewers = *req.MinReviewers
+	}
+	if req.MaxReviewers != nil {
+		settings.MaxReviewers = *req.MaxReviewers
+	}
+	if req.ReviewerStrategy != nil {
+		settings.ReviewerStrategy = domain.ReviewerStrategy(*req.ReviewerStrategy)
+	}
+	if req.AutoBackfill != nil {
+		settings.AutoBackfill = *req.AutoBackfill
+	}
+	if req.MinApprovals != nil {
+		settings.MinApprovals = *req.MinApprovals
+	}
+	if req.BlockOnChangesRequested != nil {
+		settings.BlockOnChangesRequested = *req.BlockOnChangesRequested
+	}
+	if req.BlockOnNeedMoreReviewers != nil {
+		settings.BlockOnNeedMoreReviewers = *req.BlockOnNeedMoreReviewers
+	}
+	if err := validateSettings(settings); err != nil {
+		return nil, &errs.InvalidError{
+			Domain: "team settings",
+			Desc:   err.Error(),
+		}
+	}
+
+	if err := t.repo.SaveSettings(settings, actor); err != nil {
+		return nil, err
+	}
+	return toSettingsResponse(settings), nil
+}
+
+func toSettingsResponse(settings *domain.TeamSettings) *dto.TeamSettingsResponse {
+	return &dto.TeamSettingsResponse{
+		TeamName:         settings.TeamName,
+		MinReviewers:     settings.MinReviewers,
+		MaxReviewers:     settings.MaxReviewers,
+		ReviewerStrategy: string(settings.ReviewerStrategy),
+		AutoBackfill:     settings.AutoBackfill,
+
+		MinApprovals:             settings.MinApprovals,
+		BlockOnChangesRequested:  settings.BlockOnChangesRequested,
+		BlockOnNeedMoreReviewers: settings.BlockOnNeedMoreReviewers,
+	}
+}
+
+func validateSettings(settings *domain.TeamSettings) error {
+	if settings.MinReviewers < 0 {
+		return fmt.Errorf("min_reviewers cannot be negative")
+	}
+
+	if settings.MaxReviewers < 1 || settings.MaxReviewers > domain.MaxReviewersLimit {
+		return fmt.Errorf("max_reviewers must be between 1 and %d", domain.MaxReviewersLimit)
+	}
+
+	if settings.MinReviewers > settings.MaxReviewers {
+		return fmt.Errorf("min_reviewers cannot be greater than max_reviewers")
+	}
+
+	if settings.MinApprovals < 0 || settings.MinApprovals > domain.MaxReviewersLimit {
+		return fmt.Errorf("min_approvals must be between 0 and %d", domain.MaxReviewersLimit)
+	}
+
+	// больше одобрений, чем ревьюверов на PR, не набрать - merge стал бы невозможен
+	if settings.MinApprovals > settings.MaxReviewers {
+		return fmt.Errorf("min_approvals cannot be greater than max_reviewers")
+	}
+
+	// пустая стратегия - сброс на стратегию по умолчанию
+	if settings.ReviewerStrategy != "" {
+		if _, ok := domain.ParseReviewerStrategy(string(settings.ReviewerStrategy)); !ok {
+			return fmt.Errorf("unknown reviewer_strategy '%s'", settings.ReviewerStrategy)
+		}
+	}
+
+	return nil
+}
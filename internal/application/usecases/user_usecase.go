@@ -1,28 +1,315 @@
 package usecases
 
 import (
+	"fmt"
 	"pr-manage-service/internal/domain"
 	"pr-manage-service/internal/interfaces/dto"
+	"pr-manage-service/pkg/errs"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type useUseCase struct {
-	repo domain.UserRepository
-	prUC domain.PRService
+	repo      domain.UserRepository
+	prUC      domain.PRService
+	selectors *domain.ReviewerSelectors
 }
 
-func NewUserUseCase(repo domain.UserRepository, prUC domain.PRService) domain.UserService {
+func NewUserUseCase(repo domain.UserRepository, prUC domain.PRService, selectors *domain.ReviewerSelectors) domain.UserService {
 	return &useUseCase{
-		repo: repo,
-		prUC: prUC,
+		repo:      repo,
+		prUC:      prUC,
+		selectors: selectors,
 	}
 }
 
 // GetReview implements domain.UserService.
-func (u *useUseCase) GetReview(teamName string, userID string) (*dto.UserPRsResponse, error) {
-	return u.prUC.GetPRsByUser(userID, teamName)
+func (u *useUseCase) GetReview(req *dto.UserPRsRequest) (*dto.UserPRsResponse, error) {
+	return u.prUC.GetPRsByUser(req)
 }
 
 // SetIsActive implements domain.UserService.
-func (u *useUseCase) SetIsActive(teamName string, userID string, v bool) (string, error) {
-	return u.repo.ChangeActive(teamName, userID, v)
+func (u *useUseCase) SetIsActive(teamName string, userID string, v bool, actor domain.Actor) (*dto.UserFullResponse, error) {
+	change, err := u.repo.ChangeActive(teamName, userID, v, actor, u.selectors)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.UserFullResponse{
+		User: dto.UserResponse{
+			UserRequest: &dto.UserRequest{
+				UserID:   userID,
+				TeamName: change.TeamName,
+				IsActive: v,
+			},
+			UserName: change.UserName,
+		},
+		AssignedPRs:   change.AssignedPRs,
+		Reassignments: toReassignmentResponses(change.Reassignments),
+	}, nil
+}
+
+// BulkDeactivate implements domain.UserService.
+func (u *useUseCase) BulkDeactivate(req *dto.BulkDeactivateRequest, actor domain.Actor) (*dto.BulkDeactivateResponse, error) {
+	if err := validateBulkDeactivate(req); err != nil {
+		return nil, &errs.InvalidError{
+			Domain: "bulk deactivate",
+			Desc:   err.Error(),
+		}
+	}
+	reassignments, err := u.repo.BulkDeactivate(req.TeamName, req.UserIDs, actor, u.selectors)
+	if err != nil {
+		return nil, err
+	}
+	resp := toReassignmentResponses(reassignments)
+	if resp == nil {
+		resp = []dto.ReassignmentResponse{}
+	}
+	return &dto.BulkDeactivateResponse{
+		TeamName:      req.TeamName,
+		Deactivated:   req.UserIDs,
+		Reassignments: resp,
+	}, nil
+}
+
+func validateBulkDeactivate(req *dto.BulkDeactivateRequest) error {
+	if strings.TrimSpace(req.TeamName) == "" {
+		return fmt.Errorf("team_name cannot be empty")
+	}
+
+	if len(req.UserIDs) == 0 {
+		return fmt.Errorf("user_ids cannot be empty")
+	}
+
+	if len(req.UserIDs) > 100 {
+		return fmt.Errorf("cannot deactivate more than 100 users at once")
+	}
+
+	userIDs := make(map[string]bool, len(req.UserIDs))
+	for _, userID := range req.UserIDs {
+		if userIDs[userID] {
+			return fmt.Errorf("duplicate user_id '%s'", userID)
+		}
+		userIDs[userID] = true
+	}
+
+	return nil
+}
+
+// Transfer implements domain.UserService.
+func (u *useUseCase) Transfer(req *dto.UserTransferRequest) (*dto.UserTransferResponse, error) {
+	if err := validateTransfer(req); err != nil {
+		return nil, &errs.InvalidError{
+			Domain: "transfer",
+			Desc:   err.Error(),
+		}
+	}
+	transfer, err := u.repo.Transfer(req.FromTeam, req.UserID, req.ToTeam, u.selectors)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.UserTransferResponse{
+		User: dto.UserResponse{
+			UserRequest: &dto.UserRequest{
+				UserID:   transfer.User.UserID,
+				TeamName: transfer.User.TeamName,
+				IsActive: transfer.User.IsActive,
+			},
+			UserName: transfer.User.UserName,
+		},
+		PreviousTeam:  req.FromTeam,
+		AssignedPRs:   transfer.AssignedPRs,
+		Reassignments: toReassignmentResponses(transfer.Reassignments),
+	}, nil
+}
+
+func validateTransfer(req *dto.UserTransferRequest) error {
+	if strings.TrimSpace(req.UserID) == "" {
+		return fmt.Errorf("user_id cannot be empty")
+	}
+
+	if strings.TrimSpace(req.FromTeam) == "" || strings.TrimSpace(req.ToTeam) == "" {
+		return fmt.Errorf("from_team and to_team cannot be empty")
+	}
+
+	if req.FromTeam == req.ToTeam {
+		return fmt.Errorf("from_team and to_team must differ")
+	}
+
+	return nil
+}
+
+// Handover implements domain.UserService.
+func (u *useUseCase) Handover(req *dto.UserHandoverRequest, actor domain.Actor) (*dto.UserHandoverResponse, error) {
+	if err := validateHandover(req); err != nil {
+		return nil, &errs.InvalidError{
+			Domain: "handover",
+			Desc:   err.Error(),
+		}
+	}
+	results, err := u.repo.Handover(req.UserID, req.TeamName, req.ToUserID, req.Reason, actor, u.selectors)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dto.UserHandoverResponse{
+		UserID:  req.UserID,
+		Results: make([]dto.HandoverResultResponse, len(results)),
+	}
+	for index, r := range results {
+		item := dto.HandoverResultResponse{
+			PullRequestID:     r.PrID,
+			TeamName:          r.TeamName,
+			Status:            "reassigned",
+			NewReviewerID:     r.NewReviewerID,
+			NeedMoreReviewers: r.NeedMoreReviewers,
+		}
+		if derr, ok := r.Err.(*errs.DomainError); ok {
+			item.Status = "skipped"
+			item.Error = &dto.ErrorResponseBody{Code: derr.Code, Msg: derr.Error()}
+			resp.Skipped++
+		} else {
+			resp.Reassigned++
+		}
+		resp.Results[index] = item
+	}
+	return resp, nil
+}
+
+func validateHandover(req *dto.UserHandoverRequest) error {
+	if strings.TrimSpace(req.UserID) == "" {
+		return fmt.Errorf("user_id cannot be empty")
+	}
+
+	if req.ToUserID == req.UserID {
+		return fmt.Errorf("to_user_id must differ from user_id")
+	}
+
+	if len(req.Reason) > 500 {
+		return fmt.Errorf("reason is too long (max 500 characters)")
+	}
+
+	return nil
+}
+
+// AddAvailability implements domain.UserService.
+func (u *useUseCase) AddAvailability(req *dto.AvailabilityRequest, actor domain.Actor) (*dto.AvailabilityResponse, error) {
+	err := validateAvailability(req)
+	if err == nil && strings.TrimSpace(req.UserID) == "" {
+		err = fmt.Errorf("user_id cannot be empty")
+	}
+	if err != nil {
+		return nil, &errs.InvalidError{
+			Domain: "availability",
+			Desc:   err.Error(),
+		}
+	}
+	period, err := u.repo.AddAvailability(toAvailability(req), actor)
+	if err != nil {
+		return nil, err
+	}
+	resp := toAvailabilityResponse(*period, time.Now())
+	return &resp, nil
+}
+
+// ListAvailability implements domain.UserService.
+func (u *useUseCase) ListAvailability(userID string) (*dto.AvailabilityListResponse, error) {
+	periods, err := u.repo.ListAvailability(userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	resp := &dto.AvailabilityListResponse{
+		UserID:  userID,
+		Periods: make([]dto.AvailabilityResponse, len(periods)),
+	}
+	for index, period := range periods {
+		resp.Periods[index] = toAvailabilityResponse(period, now)
+	}
+	return resp, nil
+}
+
+// UpdateAvailability implements domain.UserService.
+func (u *useUseCase) UpdateAvailability(req *dto.AvailabilityRequest, actor domain.Actor) (*dto.AvailabilityResponse, error) {
+	err := validateAvailability(req)
+	if err == nil && req.ID <= 0 {
+		err = fmt.Errorf("id must be positive")
+	}
+	if err != nil {
+		return nil, &errs.InvalidError{
+			Domain: "availability",
+			Desc:   err.Error(),
+		}
+	}
+	period, err := u.repo.UpdateAvailability(toAvailability(req), actor)
+	if err != nil {
+		return nil, err
+	}
+	resp := toAvailabilityResponse(*period, time.Now())
+	return &resp, nil
+}
+
+// DeleteAvailability implements domain.UserService.
+func (u *useUseCase) DeleteAvailability(id string, actor domain.Actor) (*dto.AvailabilityResponse, error) {
+	periodID, err := strconv.Atoi(id)
+	if err != nil || periodID <= 0 {
+		return nil, &errs.InvalidError{
+			Domain: "availability",
+			Desc:   "id must be a positive integer",
+		}
+	}
+	period, err := u.repo.DeleteAvailability(periodID, actor)
+	if err != nil {
+		return nil, err
+	}
+	resp := toAvailabilityResponse(*period, time.Now())
+	return &resp, nil
+}
+
+// AutoHandover implements domain.UserService.
+func (u *useUseCase) AutoHandover() (int, error) {
+	return u.repo.StartHandovers(u.selectors)
+}
+
+func validateAvailability(req *dto.AvailabilityRequest) error {
+	if req.StartsAt.IsZero() || req.EndsAt.IsZero() {
+		return fmt.Errorf("starts_at and ends_at are required")
+	}
+
+	if !req.EndsAt.After(req.StartsAt) {
+		return fmt.Errorf("ends_at must be after starts_at")
+	}
+
+	if !req.EndsAt.After(time.Now()) {
+		return fmt.Errorf("ends_at must be in the future")
+	}
+
+	if len(req.Reason) > 500 {
+		return fmt.Errorf("reason is too long (max 500 characters)")
+	}
+
+	return nil
+}
+
+func toAvailability(req *dto.AvailabilityRequest) *domain.Availability {
+	return &domain.Availability{
+		ID:           req.ID,
+		UserID:       req.UserID,
+		StartsAt:     req.StartsAt,
+		EndsAt:       req.EndsAt,
+		Reason:       req.Reason,
+		AutoHandover: req.AutoHandover,
+	}
+}
+
+func toAvailabilityResponse(period domain.Availability, now time.Time) dto.AvailabilityResponse {
+	return dto.AvailabilityResponse{
+		ID:           period.ID,
+		UserID:       period.UserID,
+		StartsAt:     period.StartsAt,
+		EndsAt:       period.EndsAt,
+		Reason:       period.Reason,
+		AutoHandover: period.AutoHandover,
+		HandedOverAt: period.HandedOverAt,
+		Current:      period.Current(now),
+	}
 }
@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// Availability - период недоступности пользователя [StartsAt, EndsAt).
+// Пока период идёт, пользователь не назначается ревьювером ни в одной команде.
+type Availability struct {
+	ID           int
+	UserID       string
+	StartsAt     time.Time
+	EndsAt       time.Time
+	Reason       string
+	AutoHandover bool       // передать OPEN ревью пользователя, когда период начнётся
+	HandedOverAt *time.Time // nil, пока ревью не переданы
+}
+
+// Current сообщает, идёт ли период в момент now
+func (a Availability) Current(now time.Time) bool {
+	return !now.Before(a.StartsAt) && now.Before(a.EndsAt)
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAvailabilityCurrent(t *testing.T) {
+	start := time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC)
+	period := Availability{StartsAt: start, EndsAt: start.Add(24 * time.Hour)}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{name: "before start", now: start.Add(-time.Second), want: false},
+		{name: "at start", now: start, want: true},
+		{name: "inside", now: start.Add(12 * time.Hour), want: true},
+		{name: "at end", now: start.Add(24 * time.Hour), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := period.Current(tt.now); got != tt.want {
+				t.Errorf("Current(%v) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
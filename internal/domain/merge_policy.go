@@ -0,0 +1,44 @@
+package domain
+
+// Правила merge-политики; возвращаются клиенту при блокировке merge
+const (
+	RuleMinApprovals      = "min_approvals"
+	RuleChangesRequested  = "no_changes_requested"
+	RuleNeedMoreReviewers = "enough_reviewers"
+)
+
+// MergePolicy - условия, при которых OPEN PR команды можно смержить.
+// Нулевое значение ничего не блокирует.
+type MergePolicy struct {
+	MinApprovals             int
+	BlockOnChangesRequested  bool
+	BlockOnNeedMoreReviewers bool
+}
+
+// Violations возвращает правила, которые нарушает PR с текущими решениями ревьюверов
+func (p MergePolicy) Violations(pr *PullRequest, reviewers []ReviewerState) []string {
+	var (
+		violations       []string
+		approvals        int
+		changesRequested bool
+	)
+	for _, r := range reviewers {
+		switch r.State {
+		case APPROVED:
+			approvals++
+		case CHANGES_REQUESTED:
+			changesRequested = true
+		}
+	}
+
+	if approvals < p.MinApprovals {
+		violations = append(violations, RuleMinApprovals)
+	}
+	if p.BlockOnChangesRequested && changesRequested {
+		violations = append(violations, RuleChangesRequested)
+	}
+	if p.BlockOnNeedMoreReviewers && pr.NeedMoreReviewers {
+		violations = append(violations, RuleNeedMoreReviewers)
+	}
+	return violations
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergePolicyViolations(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    MergePolicy
+		needMore  bool
+		reviewers []ReviewerState
+		want      []string
+	}{
+		{
+			name:   "zero policy allows PR without reviewers",
+			policy: MergePolicy{},
+			want:   nil,
+		},
+		{
+			name:      "not enough approvals",
+			policy:    MergePolicy{MinApprovals: 2},
+			reviewers: []ReviewerState{{UserID: "u2", State: APPROVED}, {UserID: "u3", State: COMMENTED}},
+			want:      []string{RuleMinApprovals},
+		},
+		{
+			name:      "changes requested blocks even with approvals",
+			policy:    MergePolicy{MinApprovals: 1, BlockOnChangesRequested: true},
+			reviewers: []ReviewerState{{UserID: "u2", State: APPROVED}, {UserID: "u3", State: CHANGES_REQUESTED}},
+			want:      []string{RuleChangesRequested},
+		},
+		{
+			name:      "changes requested ignored when rule is off",
+			policy:    MergePolicy{MinApprovals: 1},
+			reviewers: []ReviewerState{{UserID: "u2", State: APPROVED}, {UserID: "u3", State: CHANGES_REQUESTED}},
+			want:      nil,
+		},
+		{
+			name:      "all rules fail",
+			policy:    MergePolicy{MinApprovals: 1, BlockOnChangesRequested: true, BlockOnNeedMoreReviewers: true},
+			needMore:  true,
+			reviewers: []ReviewerState{{UserID: "u2", State: CHANGES_REQUESTED}},
+			want:      []string{RuleMinApprovals, RuleChangesRequested, RuleNeedMoreReviewers},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.policy.Violations(&PullRequest{NeedMoreReviewers: tt.needMore}, tt.reviewers)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Violations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
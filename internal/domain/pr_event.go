@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+type PREventType string
+
+const (
+	EventCreated          PREventType = "created"
+	EventReady            PREventType = "ready"             // черновик переведён в OPEN
+	EventReviewerAssigned PREventType = "reviewer_assigned" // при создании, ready или добор (auto_backfill)
+	EventReviewerReplaced PREventType = "reviewer_replaced"
+	EventReviewerRemoved  PREventType = "reviewer_removed" // снят без замены: кандидата не нашлось
+	EventReviewSubmitted  PREventType = "review_submitted"
+	EventMerged           PREventType = "merged"
+	EventClosed           PREventType = "closed"
+	EventReopened         PREventType = "reopened"
+)
+
+// PREvent - запись истории PR; история только дописывается
+type PREvent struct {
+	PrID          string
+	Type          PREventType
+	ActorID       string // user_id инициатора; пусто - система или администратор без подписанного Actor-Id
+	ReviewerID    string // ревьювер, которого касается событие
+	NewReviewerID string // замена для reviewer_replaced
+	Details       string // решение ревьювера для review_submitted
+	Reason        string // причина переназначения, указанная в /pullRequest/reassign
+	CreatedAt     time.Time
+}
@@ -0,0 +1,85 @@
+package domain
+
+import "time"
+
+type PRSort string
+
+const (
+	SortCreatedDesc PRSort = "created_at_desc"
+	SortCreatedAsc  PRSort = "created_at_asc"
+	SortUpdatedDesc PRSort = "updated_at_desc"
+	SortUpdatedAsc  PRSort = "updated_at_asc"
+)
+
+// ParsePRSort проверяет порядок сортировки; пустая строка - сначала новые
+func ParsePRSort(s string) (PRSort, bool) {
+	if s == "" {
+		return SortCreatedDesc, true
+	}
+	switch sort := PRSort(s); sort {
+	case SortCreatedDesc, SortCreatedAsc, SortUpdatedDesc, SortUpdatedAsc:
+		return sort, true
+	}
+	return "", false
+}
+
+// PRPosition - позиция keyset-пагинации: значение поля сортировки и id последнего PR страницы
+type PRPosition struct {
+	Time time.Time
+	PrID string
+}
+
+// PRListFilter - фильтры списка PR; пустые поля не ограничивают выборку.
+// Диапазон [CreatedFrom, CreatedTo) применяется к prs.created_at.
+type PRListFilter struct {
+	TeamName          string
+	Status            STATUS
+	AuthorID          string
+	ReviewerID        string
+	NeedMoreReviewers *bool
+	CreatedFrom       *time.Time
+	CreatedTo         *time.Time
+	Sort              PRSort
+	After             *PRPosition // nil - с начала списка
+	Limit             int
+}
+
+type PRListItem struct {
+	PullRequest
+	Reviewers []string
+}
+
+// PRRole - роль пользователя в PR
+type PRRole string
+
+const (
+	RoleAuthor   PRRole = "author"
+	RoleReviewer PRRole = "reviewer"
+	RoleAny      PRRole = "any" // только для фильтра
+)
+
+// ParsePRRole проверяет фильтр по роли; пустая строка - любая роль
+func ParsePRRole(s string) (PRRole, bool) {
+	if s == "" {
+		return RoleAny, true
+	}
+	switch role := PRRole(s); role {
+	case RoleAuthor, RoleReviewer, RoleAny:
+		return role, true
+	}
+	return "", false
+}
+
+// UserPRsFilter - фильтры PR пользователя; PR отдаются от новых к старым
+type UserPRsFilter struct {
+	Role   PRRole
+	Status STATUS      // пустой - любой статус
+	After  *PRPosition // позиция по created_at
+	Limit  int
+}
+
+type UserPR struct {
+	PullRequest
+	Role      PRRole // author или reviewer
+	Reviewers []ReviewerState
+}
@@ -10,6 +10,17 @@ type STATUS string
 const (
 	OPEN   STATUS = "OPEN"
 	MERGED STATUS = "MERGED"
+	CLOSED STATUS = "CLOSED"
+	DRAFT  STATUS = "DRAFT" // без ревьюверов и нагрузки до /pullRequest/ready
+)
+
+type ReviewState string
+
+const (
+	APPROVED          ReviewState = "APPROVED"
+	CHANGES_REQUESTED ReviewState = "CHANGES_REQUESTED"
+	COMMENTED         ReviewState = "COMMENTED"
+	PENDING           ReviewState = "PENDING" // ревьювер назначен, но решения ещё нет
 )
 
 type PullRequest struct {
@@ -21,18 +32,53 @@ type PullRequest struct {
 	NeedMoreReviewers bool
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
+	MergedAt          *time.Time // nil, пока PR не смержен
+}
+
+// Reassignment - замена ревьювера в одном PR.
+// Пустой NewReviewerID означает, что замены не нашлось и ревьювер просто снят.
+type Reassignment struct {
+	PrID              string
+	OldReviewerID     string
+	NewReviewerID     string
+	NeedMoreReviewers bool
+}
+
+// ReviewerState - текущее (последнее) решение назначенного ревьювера
+type ReviewerState struct {
+	UserID      string
+	AssignedAt  time.Time
+	State       ReviewState
+	SubmittedAt *time.Time
 }
 
 type PRService interface {
-	GetPRsByUser(userID, teamName string) (*dto.UserPRsResponse, error)
+	GetPRsByUser(req *dto.UserPRsRequest) (*dto.UserPRsResponse, error)
 	Create(*dto.PRCreateRequest) (*dto.PRResponse, error)
-	Merge(*dto.PRCreateRequest) (*dto.PRMergeResponse, error)
-	Reassign(prID string, oldRevID string) (*dto.PRReassignResponse, error)
+	Merge(req *dto.PRCreateRequest, actor Actor) (*dto.PRMergeResponse, error)
+	Reassign(req *dto.PRReassignRequest, actor Actor) (*dto.PRReassignResponse, error)
+	Review(req *dto.PRReviewRequest, actor Actor) (*dto.PRResponse, error)
+	Close(prID string, actor Actor) (*dto.PRResponse, error)
+	Reopen(prID string, actor Actor) (*dto.PRReopenResponse, error)
+	Ready(prID string, actor Actor) (*dto.PRResponse, error)
+	Get(prID string) (*dto.PRDetailsResponse, error)
+	History(prID string) (*dto.PRHistoryResponse, error)
+	List(req *dto.PRListRequest) (*dto.PRListResponse, error)
 }
 
 type PRRepository interface {
-	GetWithUser(*User) (*[]PullRequest, error)
-	CreateNewPR(*PullRequest) (assigned_reviewers []string, err error)
-	Merge(prID string) (pr *PullRequest, assigned_reviewers []string, err error)
-	Reassign(prID string, userID string) (pr *PullRequest, assigned_reviewers []string, replacedUserID string, err error)
+	GetWithUser(user *User, filter UserPRsFilter) ([]UserPR, error)
+	CreateNewPR(pr *PullRequest, selectors *ReviewerSelectors) (reviewers []ReviewerState, err error)
+	// Merge доступен мейнтейнерам и лидам команды PR (или администратору)
+	Merge(prID string, actor Actor) (pr *PullRequest, reviewers []ReviewerState, err error)
+	// Изменения PR записываются в его историю от имени actor.UserID
+	// Reassign заменяет ревьювера userID на newUserID, а если он пуст - на кандидата по стратегии команды
+	Reassign(prID, userID, newUserID, reason string, actor Actor, selectors *ReviewerSelectors) (pr *PullRequest, reviewers []ReviewerState, replacedUserID string, err error)
+	SubmitReview(prID string, reviewerID string, state ReviewState, body string) (pr *PullRequest, reviewers []ReviewerState, err error)
+	Close(prID string, actor Actor) (pr *PullRequest, reviewers []ReviewerState, err error)
+	Reopen(prID string, actor Actor, selectors *ReviewerSelectors) (pr *PullRequest, reviewers []ReviewerState, reassignments []Reassignment, err error)
+	Ready(prID string, actor Actor, selectors *ReviewerSelectors) (pr *PullRequest, reviewers []ReviewerState, err error)
+	Get(prID string) (pr *PullRequest, reviewers []ReviewerState, err error)
+	History(prID string) ([]PREvent, error)
+	List(filter PRListFilter) ([]PRListItem, error)
 }
@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+type ReviewerStrategy string
+
+const (
+	FirstN      ReviewerStrategy = "first_n"
+	Random      ReviewerStrategy = "random"
+	RoundRobin  ReviewerStrategy = "round_robin"
+	LeastLoaded ReviewerStrategy = "least_loaded"
+)
+
+// ParseReviewerStrategy проверяет, что стратегия известна сервису
+func ParseReviewerStrategy(s string) (ReviewerStrategy, bool) {
+	switch strategy := ReviewerStrategy(s); strategy {
+	case FirstN, Random, RoundRobin, LeastLoaded:
+		return strategy, true
+	}
+	return "", false
+}
+
+type ReviewerCandidate struct {
+	UserID         string
+	OpenReviews    int
+	LastAssignedAt time.Time // нулевое значение - ревью ещё ни разу не назначалось
+}
+
+// ReviewerSelector выбирает до n ревьюверов из candidates.
+// Кандидаты приходят в стабильном порядке (порядок добавления в команду).
+type ReviewerSelector interface {
+	Select(candidates []ReviewerCandidate, n int) []ReviewerCandidate
+}
+
+type firstNSelector struct{}
+
+// NewFirstNSelector берёт первых n кандидатов
+func NewFirstNSelector() ReviewerSelector {
+	return firstNSelector{}
+}
+
+func (firstNSelector) Select(candidates []ReviewerCandidate, n int) []ReviewerCandidate {
+	return head(candidates, n)
+}
+
+type randomSelector struct {
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// NewRandomSelector выбирает n случайных кандидатов; seed делает выбор воспроизводимым
+func NewRandomSelector(seed int64) ReviewerSelector {
+	return &randomSelector{rnd: rand.New(rand.NewSource(seed))}
+}
+
+func (s *randomSelector) Select(candidates []ReviewerCandidate, n int) []ReviewerCandidate {
+	shuffled := append([]ReviewerCandidate(nil), candidates...)
+	s.mu.Lock()
+	s.rnd.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	s.mu.Unlock()
+	return head(shuffled, n)
+}
+
+type roundRobinSelector struct{}
+
+// NewRoundRobinSelector идёт по кругу: первым выбирается тот, кому ревью назначали давнее всех
+func NewRoundRobinSelector() ReviewerSelector {
+	return roundRobinSelector{}
+}
+
+func (roundRobinSelector) Select(candidates []ReviewerCandidate, n int) []ReviewerCandidate {
+	sorted := append([]ReviewerCandidate(nil), candidates...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].LastAssignedAt.Before(sorted[j].LastAssignedAt)
+	})
+	return head(sorted, n)
+}
+
+type leastLoadedSelector struct{}
+
+// NewLeastLoadedSelector выбирает кандидатов с наименьшим числом OPEN ревью,
+// при равной нагрузке - по принципу round-robin
+func NewLeastLoadedSelector() ReviewerSelector {
+	return leastLoadedSelector{}
+}
+
+func (leastLoadedSelector) Select(candidates []ReviewerCandidate, n int) []ReviewerCandidate {
+	sorted := append([]ReviewerCandidate(nil), candidates...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].OpenReviews != sorted[j].OpenReviews {
+			return sorted[i].OpenReviews < sorted[j].OpenReviews
+		}
+		return sorted[i].LastAssignedAt.Before(sorted[j].LastAssignedAt)
+	})
+	return head(sorted, n)
+}
+
+func head(candidates []ReviewerCandidate, n int) []ReviewerCandidate {
+	if n < 0 {
+		n = 0
+	}
+	if len(candidates) > n {
+		return candidates[:n]
+	}
+	return candidates
+}
+
+// ReviewerSelectors хранит встроенные стратегии и стратегию по умолчанию,
+// которая используется для команд без собственной настройки.
+type ReviewerSelectors struct {
+	Default   ReviewerStrategy
+	selectors map[ReviewerStrategy]ReviewerSelector
+}
+
+func NewReviewerSelectors(defaultStrategy ReviewerStrategy, seed int64) *ReviewerSelectors {
+	return &ReviewerSelectors{
+		Default: defaultStrategy,
+		selectors: map[ReviewerStrategy]ReviewerSelector{
+			FirstN:      NewFirstNSelector(),
+			Random:      NewRandomSelector(seed),
+			RoundRobin:  NewRoundRobinSelector(),
+			LeastLoaded: NewLeastLoadedSelector(),
+		},
+	}
+}
+
+// For возвращает селектор стратегии; пустая или неизвестная стратегия - стратегия по умолчанию
+func (s *ReviewerSelectors) For(strategy ReviewerStrategy) ReviewerSelector {
+	if selector, ok := s.selectors[strategy]; ok {
+		return selector
+	}
+	return s.selectors[s.Default]
+}
@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func userIDs(candidates []ReviewerCandidate) []string {
+	ids := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		ids = append(ids, c.UserID)
+	}
+	return ids
+}
+
+func TestReviewerSelectors(t *testing.T) {
+	now := time.Now()
+	candidates := []ReviewerCandidate{
+		{UserID: "u1", OpenReviews: 3, LastAssignedAt: now},
+		{UserID: "u2", OpenReviews: 1, LastAssignedAt: now.Add(-time.Hour)},
+		{UserID: "u3", OpenReviews: 1, LastAssignedAt: now.Add(-2 * time.Hour)},
+		{UserID: "u4", OpenReviews: 0},
+	}
+
+	tests := []struct {
+		name     string
+		selector ReviewerSelector
+		n        int
+		want     []string
+	}{
+		{
+			name:     "first n keeps input order",
+			selector: NewFirstNSelector(),
+			n:        2,
+			want:     []string{"u1", "u2"},
+		},
+		{
+			name:     "round robin starts with never assigned",
+			selector: NewRoundRobinSelector(),
+			n:        3,
+			want:     []string{"u4", "u3", "u2"},
+		},
+		{
+			name:     "least loaded breaks ties by last assignment",
+			selector: NewLeastLoadedSelector(),
+			n:        3,
+			want:     []string{"u4", "u3", "u2"},
+		},
+		{
+			name:     "n larger than candidates",
+			selector: NewLeastLoadedSelector(),
+			n:        10,
+			want:     []string{"u4", "u3", "u2", "u1"},
+		},
+		{
+			name:     "zero reviewers",
+			selector: NewFirstNSelector(),
+			n:        0,
+			want:     []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := userIDs(tt.selector.Select(candidates, tt.n))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Select() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRandomSelectorSeed(t *testing.T) {
+	candidates := []ReviewerCandidate{{UserID: "u1"}, {UserID: "u2"}, {UserID: "u3"}, {UserID: "u4"}, {UserID: "u5"}}
+
+	first := userIDs(NewRandomSelector(42).Select(candidates, 2))
+	second := userIDs(NewRandomSelector(42).Select(candidates, 2))
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("same seed gave different picks: %v and %v", first, second)
+	}
+	if len(first) != 2 || first[0] == first[1] {
+		t.Errorf("expected 2 distinct reviewers, got %v", first)
+	}
+	if candidates[0].UserID != "u1" {
+		t.Error("Select() must not reorder input candidates")
+	}
+}
+
+func TestReviewerSelectorsFallback(t *testing.T) {
+	selectors := NewReviewerSelectors(FirstN, 1)
+	if _, ok := selectors.For("").(firstNSelector); !ok {
+		t.Error("empty strategy must fall back to default")
+	}
+	if _, ok := selectors.For(LeastLoaded).(leastLoadedSelector); !ok {
+		t.Error("explicit strategy must win over default")
+	}
+}
@@ -0,0 +1,53 @@
+package domain
+
+// TeamRole - роль участника в команде
+type TeamRole string
+
+const (
+	RoleLead       TeamRole = "lead"       // управляет активностью участников, может быть обязательным ревьювером
+	RoleMaintainer TeamRole = "maintainer" // мержит PR команды
+	RoleMember     TeamRole = "member"
+)
+
+// ParseTeamRole проверяет роль; пустая строка - роль по умолчанию (member)
+func ParseTeamRole(s string) (TeamRole, bool) {
+	switch role := TeamRole(s); role {
+	case "":
+		return RoleMember, true
+	case RoleLead, RoleMaintainer, RoleMember:
+		return role, true
+	}
+	return "", false
+}
+
+// CanMerge - лид обладает правами мейнтейнера
+func (r TeamRole) CanMerge() bool {
+	return r == RoleLead || r == RoleMaintainer
+}
+
+// CanManageMembers - менять активность и роли участников команды может только лид
+func (r TeamRole) CanManageMembers() bool {
+	return r == RoleLead
+}
+
+// SetsRoles - роль или mandatory_reviewer передаются хотя бы у одного участника;
+// менять их может только лид команды или администратор
+func SetsRoles(members []User) bool {
+	for _, m := range members {
+		if m.Role != "" || m.MandatoryReviewer {
+			return true
+		}
+	}
+	return false
+}
+
+// Actor - инициатор запроса: участник команды (подписанный заголовок Actor-Id) или администратор (Admin-Token)
+type Actor struct {
+	UserID string // только подтверждённый подписью пользователь; пусто - не аутентифицирован
+	Admin  bool   // администратор проходит проверки ролей
+}
+
+// Is - инициатор аутентифицирован как пользователь userID
+func (a Actor) Is(userID string) bool {
+	return a.UserID != "" && a.UserID == userID
+}
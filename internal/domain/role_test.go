@@ -0,0 +1,58 @@
+package domain
+
+import "testing"
+
+func TestTeamRolePermissions(t *testing.T) {
+	tests := []struct {
+		role          string
+		valid         bool
+		canMerge      bool
+		canManageTeam bool
+	}{
+		{role: "", valid: true},
+		{role: "member", valid: true},
+		{role: "maintainer", valid: true, canMerge: true},
+		{role: "lead", valid: true, canMerge: true, canManageTeam: true},
+		{role: "owner", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			role, ok := ParseTeamRole(tt.role)
+			if ok != tt.valid {
+				t.Fatalf("ParseTeamRole(%q) ok = %v, want %v", tt.role, ok, tt.valid)
+			}
+			if !ok {
+				return
+			}
+			if got := role.CanMerge(); got != tt.canMerge {
+				t.Errorf("CanMerge() = %v, want %v", got, tt.canMerge)
+			}
+			if got := role.CanManageMembers(); got != tt.canManageTeam {
+				t.Errorf("CanManageMembers() = %v, want %v", got, tt.canManageTeam)
+			}
+		})
+	}
+}
+
+func TestActorIs(t *testing.T) {
+	tests := []struct {
+		name   string
+		actor  Actor
+		userID string
+		want   bool
+	}{
+		{name: "same user", actor: Actor{UserID: "u1"}, userID: "u1", want: true},
+		{name: "other user", actor: Actor{UserID: "u1"}, userID: "u2"},
+		{name: "not authenticated", actor: Actor{}, userID: ""},
+		{name: "admin without user", actor: Actor{Admin: true}, userID: "u1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.actor.Is(tt.userID); got != tt.want {
+				t.Errorf("Is(%q) = %v, want %v", tt.userID, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"pr-manage-service/internal/interfaces/dto"
+	"time"
+)
+
+// StatsFilter - фильтры статистики; пустые поля не ограничивают выборку.
+// Диапазон [From, To) применяется к pr_reviewers.assigned_at для назначений
+// и к prs.created_at для показателей по PR.
+type StatsFilter struct {
+	TeamName string
+	From     *time.Time
+	To       *time.Time
+}
+
+type UserCount struct {
+	UserID   string
+	TeamName string
+	Count    int
+}
+
+type TeamPRCount struct {
+	TeamName string
+	Open     int
+	Merged   int
+}
+
+type Stats struct {
+	ReviewAssignments []UserCount
+	TeamPRs           []TeamPRCount
+	AuthorPRs         []UserCount
+	NeedMoreReviewers int
+}
+
+type StatsService interface {
+	GetStats(teamName, from, to string) (*dto.StatsResponse, error)
+}
+
+type StatsRepository interface {
+	GetStats(filter StatsFilter) (*Stats, error)
+}
@@ -4,17 +4,86 @@ import (
 	"pr-manage-service/internal/interfaces/dto"
 )
 
+const (
+	DefaultMinReviewers = 2
+	DefaultMaxReviewers = 2
+	MaxReviewersLimit   = 10
+)
+
 type Team struct {
 	TeamName string
 	Members  []User
 }
 
+// TeamSettings - настройки назначения ревьюверов в команде.
+// PR получает до MaxReviewers ревьюверов и помечается need_more_reviewers, пока их меньше MinReviewers.
+type TeamSettings struct {
+	TeamName         string
+	MinReviewers     int
+	MaxReviewers     int
+	ReviewerStrategy ReviewerStrategy // пустая - стратегия по умолчанию из конфигурации
+	AutoBackfill     bool             // активированный участник сразу добирается в OPEN PR с need_more_reviewers
+	MergePolicy
+}
+
+func DefaultTeamSettings(teamName string) TeamSettings {
+	return TeamSettings{
+		TeamName:     teamName,
+		MinReviewers: DefaultMinReviewers,
+		MaxReviewers: DefaultMaxReviewers,
+	}
+}
+
+// MembersChange - результат добавления и обновления участников команды
+type MembersChange struct {
+	Added         []string
+	Updated       []string
+	Reassignments []Reassignment // OPEN ревью участников, ставших неактивными
+	AssignedPRs   []string       // OPEN PR, куда добраны новые и активированные участники
+}
+
+// TeamSummary - команда в списке /team/list
+type TeamSummary struct {
+	TeamName      string
+	Members       int
+	ActiveMembers int
+	OpenPRs       int
+}
+
+// TeamListFilter - фильтр списка команд; команды идут по имени, After - имя последней команды предыдущей страницы
+type TeamListFilter struct {
+	Prefix string
+	After  string
+	Limit  int
+}
+
+type TeamDeleteMode string
+
+const (
+	DeleteHard    TeamDeleteMode = "hard"    // команда, участники, PR и история удаляются
+	DeleteArchive TeamDeleteMode = "archive" // команда скрывается, её PR доступны только на чтение
+)
+
 type TeamService interface {
-	AddTeam(team *dto.TeamRequest) error
+	AddTeam(team *dto.TeamRequest, actor Actor) error
 	GetTeamByName(teamName string) (*dto.TeamResponse, error)
+	GetSettings(teamName string) (*dto.TeamSettingsResponse, error)
+	UpdateSettings(req *dto.TeamSettingsRequest, actor Actor) (*dto.TeamSettingsResponse, error)
+	AddMembers(req *dto.TeamRequest, actor Actor) (*dto.TeamMembersResponse, error)
+	RemoveMember(req *dto.TeamMemberRequest, actor Actor) (*dto.TeamMemberRemoveResponse, error)
+	RenameMember(req *dto.TeamMemberRequest, actor Actor) (*dto.UserResponse, error)
+	DeleteTeam(teamName, mode string, force bool) (*dto.TeamDeleteResponse, error)
+	ListTeams(prefix, limit, cursor string) (*dto.TeamListResponse, error)
 }
 
 type TeamRepository interface {
 	AddNewTeam(teamName string, members *[]User) error
 	GetTeamInfoByName(teamName string) (*Team, error)
+	GetSettings(teamName string) (*TeamSettings, error)
+	SaveSettings(settings *TeamSettings, actor Actor) error
+	UpsertMembers(teamName string, members []User, actor Actor, selectors *ReviewerSelectors) (*MembersChange, error)
+	RemoveMember(teamName, userID string, actor Actor, selectors *ReviewerSelectors) ([]Reassignment, error)
+	RenameMember(teamName, userID, userName string, actor Actor) (*User, error)
+	DeleteTeam(teamName string, mode TeamDeleteMode, force bool) (openPRs int, err error)
+	ListTeams(filter TeamListFilter) ([]TeamSummary, error)
 }
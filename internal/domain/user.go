@@ -4,16 +4,72 @@ import "pr-manage-service/internal/interfaces/dto"
 
 type User struct {
 	UserID   string
+	Login    string // глобальный логин; по умолчанию совпадает с UserID
 	UserName string
 	TeamName string
 	IsActive bool
+
+	Role              TeamRole // роль в команде TeamName
+	MandatoryReviewer bool     // лид назначается ревьювером каждого нового PR команды
+
+	// MaxOpenReviews - лимит OPEN ревью во всех командах; nil - без лимита.
+	// При добавлении участников nil сохраняет текущий лимит, а 0 снимает его.
+	MaxOpenReviews *int
+	OpenReviews    int // текущие OPEN ревью во всех командах; заполняется только при чтении
+}
+
+// ActiveChange - результат смены флага активности пользователя
+type ActiveChange struct {
+	TeamName      string // команда, в которой сменился флаг; определяется сама, если не передана
+	UserName      string
+	AssignedPRs   []string       // OPEN PR, куда пользователь добран ревьювером после активации
+	Reassignments []Reassignment // OPEN ревью, снятые с пользователя при деактивации
+}
+
+// Transfer - результат перевода пользователя в другую команду
+type Transfer struct {
+	User          User           // пользователь в новой команде
+	AssignedPRs   []string       // OPEN PR новой команды, куда пользователь добран ревьювером
+	Reassignments []Reassignment // OPEN ревью в прежней команде, переданные другим участникам
+}
+
+// HandoverResult - итог передачи одного OPEN ревью пользователя
+type HandoverResult struct {
+	PrID              string
+	TeamName          string
+	NewReviewerID     string
+	NeedMoreReviewers bool
+	Err               error // DomainError, если замену назначить не удалось: ревью остаётся за пользователем
 }
 
 type UserService interface {
-	SetIsActive(teamName, userID string, v bool) (string, error)
-	GetReview(teamName, userID string) (*dto.UserPRsResponse, error)
+	SetIsActive(teamName, userID string, v bool, actor Actor) (*dto.UserFullResponse, error)
+	GetReview(req *dto.UserPRsRequest) (*dto.UserPRsResponse, error)
+	BulkDeactivate(req *dto.BulkDeactivateRequest, actor Actor) (*dto.BulkDeactivateResponse, error)
+	Transfer(req *dto.UserTransferRequest) (*dto.UserTransferResponse, error)
+	Handover(req *dto.UserHandoverRequest, actor Actor) (*dto.UserHandoverResponse, error)
+	AddAvailability(req *dto.AvailabilityRequest, actor Actor) (*dto.AvailabilityResponse, error)
+	ListAvailability(userID string) (*dto.AvailabilityListResponse, error)
+	UpdateAvailability(req *dto.AvailabilityRequest, actor Actor) (*dto.AvailabilityResponse, error)
+	DeleteAvailability(id string, actor Actor) (*dto.AvailabilityResponse, error)
+	// AutoHandover передаёт ревью пользователей, чьи периоды недоступности с auto_handover уже начались
+	AutoHandover() (handedOver int, err error)
 }
 
 type UserRepository interface {
-	ChangeActive(teamName, userID string, isActive bool) (name string, err error)
+	// ChangeActive и BulkDeactivate доступны только лиду команды (или администратору)
+	ChangeActive(teamName, userID string, isActive bool, actor Actor, selectors *ReviewerSelectors) (*ActiveChange, error)
+	BulkDeactivate(teamName string, userIDs []string, actor Actor, selectors *ReviewerSelectors) ([]Reassignment, error)
+	// Transfer переводит членство пользователя userID из fromTeam в toTeam
+	Transfer(fromTeam, userID, toTeam string, selectors *ReviewerSelectors) (*Transfer, error)
+	// Handover передаёт все OPEN ревью userID (в команде teamName или во всех его командах) участнику toUserID,
+	// а если он пуст - по стратегии команды. Доступен самому пользователю, лиду команды и администратору.
+	Handover(userID, teamName, toUserID, reason string, actor Actor, selectors *ReviewerSelectors) ([]HandoverResult, error)
+	// Периодами недоступности управляет сам пользователь, лид любой его команды или администратор
+	AddAvailability(period *Availability, actor Actor) (*Availability, error)
+	ListAvailability(userID string) ([]Availability, error)
+	UpdateAvailability(period *Availability, actor Actor) (*Availability, error)
+	DeleteAvailability(id int, actor Actor) (*Availability, error)
+	// StartHandovers передаёт ревью по начавшимся периодам с auto_handover, каждый период - в своей транзакции
+	StartHandovers(selectors *ReviewerSelectors) (handedOver int, err error)
 }
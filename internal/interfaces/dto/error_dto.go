@@ -3,8 +3,9 @@ package dto
 import "pr-manage-service/pkg/codes"
 
 type ErrorResponseBody struct {
-	Code codes.CODE `json:"code"`
-	Msg  string     `json:"message"`
+	Code    codes.CODE `json:"code"`
+	Msg     string     `json:"message"`
+	Details []string   `json:"details,omitempty"`
 }
 
 type ErrorResponse struct {
@@ -9,6 +9,7 @@ type PRCreateRequest struct {
 	PullRequestName string `json:"pull_request_name"`
 	AuthorID        string `json:"author_id"`
 	TeamName        string `json:"team_name"`
+	Draft           bool   `json:"draft,omitempty"`
 }
 
 type PRResponse struct {
@@ -18,6 +19,91 @@ type PRResponse struct {
 	TeamName          string   `json:"team_name"`
 	Status            string   `json:"status"`
 	AssignedReviewers []string `json:"assigned_reviewers,omitempty"`
+
+	ReviewerStates []ReviewerStateResponse `json:"reviewer_states,omitempty"`
+}
+
+// ReviewerStateResponse - последнее решение ревьювера; PENDING, если решения ещё нет
+type ReviewerStateResponse struct {
+	ReviewerID  string     `json:"reviewer_id"`
+	State       string     `json:"state"`
+	SubmittedAt *time.Time `json:"submitted_at,omitempty"`
+}
+
+// PRDetailsResponse - полная информация о PR для /pullRequest/get
+type PRDetailsResponse struct {
+	PullRequestID     string               `json:"pull_request_id"`
+	PullRequestName   string               `json:"pull_request_name"`
+	AuthorID          string               `json:"author_id"`
+	TeamName          string               `json:"team_name"`
+	Status            string               `json:"status"`
+	NeedMoreReviewers bool                 `json:"need_more_reviewers"`
+	CreatedAt         time.Time            `json:"createdAt"`
+	UpdatedAt         time.Time            `json:"updatedAt"`
+	MergedAt          *time.Time           `json:"mergedAt"`
+	Reviewers         []PRReviewerResponse `json:"reviewers"`
+}
+
+type PRReviewerResponse struct {
+	ReviewerID  string     `json:"reviewer_id"`
+	AssignedAt  time.Time  `json:"assigned_at"`
+	State       string     `json:"state"`
+	SubmittedAt *time.Time `json:"submitted_at,omitempty"`
+}
+
+// PRHistoryResponse - история PR для /pullRequest/history, от старых событий к новым
+type PRHistoryResponse struct {
+	PullRequestID string            `json:"pull_request_id"`
+	Events        []PREventResponse `json:"events"`
+}
+
+type PREventResponse struct {
+	Type          string    `json:"type"`
+	ActorID       string    `json:"actor_id,omitempty"` // пусто - система или администратор
+	ReviewerID    string    `json:"reviewer_id,omitempty"`
+	NewReviewerID string    `json:"new_reviewer_id,omitempty"`
+	Details       string    `json:"details,omitempty"`
+	Reason        string    `json:"reason,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// PRListRequest - параметры /pullRequest/list; все поля необязательные
+type PRListRequest struct {
+	TeamName          string `form:"team_name"`
+	Status            string `form:"status"`
+	AuthorID          string `form:"author_id"`
+	ReviewerID        string `form:"reviewer_id"`
+	NeedMoreReviewers string `form:"need_more_reviewers"`
+	CreatedFrom       string `form:"created_from"`
+	CreatedTo         string `form:"created_to"`
+	Sort              string `form:"sort"`
+	Limit             string `form:"limit"`
+	Cursor            string `form:"cursor"`
+}
+
+type PRListItem struct {
+	PullRequestID     string     `json:"pull_request_id"`
+	PullRequestName   string     `json:"pull_request_name"`
+	AuthorID          string     `json:"author_id"`
+	TeamName          string     `json:"team_name"`
+	Status            string     `json:"status"`
+	NeedMoreReviewers bool       `json:"need_more_reviewers"`
+	AssignedReviewers []string   `json:"assigned_reviewers"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	UpdatedAt         time.Time  `json:"updatedAt"`
+	MergedAt          *time.Time `json:"mergedAt"`
+}
+
+type PRListResponse struct {
+	PullRequests []PRListItem `json:"pull_requests"`
+	NextCursor   string       `json:"next_cursor,omitempty"` // пусто на последней странице
+}
+
+type PRReviewRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+	ReviewerID    string `json:"reviewer_id"`
+	State         string `json:"state"`
+	Body          string `json:"body,omitempty"`
 }
 
 type PRMergeResponse struct {
@@ -25,7 +111,28 @@ type PRMergeResponse struct {
 	MergedAt time.Time `json:"mergedAt"`
 }
 
+// PRReassignRequest - без new_reviewer_id замена подбирается стратегией команды
+type PRReassignRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+	OldReviewerID string `json:"old_reviewer_id"`
+	NewReviewerID string `json:"new_reviewer_id,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+}
+
 type PRReassignResponse struct {
 	PR         PRResponse `json:"pr"`
 	ReplacedBy string     `json:"replaced_by"`
 }
+
+// ReassignmentResponse - замена ревьювера в одном PR; new_reviewer_id пуст, если замены не нашлось
+type ReassignmentResponse struct {
+	PullRequestID     string `json:"pull_request_id"`
+	OldReviewerID     string `json:"old_reviewer_id"`
+	NewReviewerID     string `json:"new_reviewer_id,omitempty"`
+	NeedMoreReviewers bool   `json:"need_more_reviewers"`
+}
+
+type PRReopenResponse struct {
+	PR            PRResponse             `json:"pr"`
+	Reassignments []ReassignmentResponse `json:"reassignments,omitempty"` // замены ревьюверов, ставших неактивными
+}
@@ -0,0 +1,20 @@
+package dto
+
+type UserStats struct {
+	UserID   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+	Count    int    `json:"count"`
+}
+
+type TeamPRStats struct {
+	TeamName string `json:"team_name"`
+	Open     int    `json:"open"`
+	Merged   int    `json:"merged"`
+}
+
+type StatsResponse struct {
+	ReviewAssignments []UserStats   `json:"review_assignments"`
+	TeamPRs           []TeamPRStats `json:"team_prs"`
+	AuthorPRs         []UserStats   `json:"author_prs"`
+	NeedMoreReviewers int           `json:"need_more_reviewers"`
+}
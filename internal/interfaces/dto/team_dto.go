@@ -7,10 +7,81 @@ type TeamRequest struct {
 
 type Member struct {
 	UserID   string `json:"user_id"`
+	Login    string `json:"login,omitempty"` // глобальный логин; по умолчанию совпадает с user_id
 	UserName string `json:"username"`
 	IsActive bool   `json:"is_active"`
+
+	Role              string `json:"role,omitempty"`               // lead, maintainer или member (по умолчанию)
+	MandatoryReviewer bool   `json:"mandatory_reviewer,omitempty"` // только для lead
+
+	MaxOpenReviews *int `json:"max_open_reviews,omitempty"` // лимит OPEN ревью во всех командах; 0 снимает лимит
+	OpenReviews    *int `json:"open_reviews,omitempty"`     // текущая нагрузка; только в ответе /team/get
 }
 
 type TeamResponse struct {
 	Team TeamRequest `json:"team"`
 }
+
+type TeamMembersResponse struct {
+	TeamName      string                 `json:"team_name"`
+	Added         []string               `json:"added"`
+	Updated       []string               `json:"updated"`
+	Reassignments []ReassignmentResponse `json:"reassignments,omitempty"`          // ревью участников, ставших неактивными
+	AssignedPRs   []string               `json:"assigned_pull_requests,omitempty"` // PR, куда добраны новые и активированные участники
+}
+
+// TeamMemberRequest - участник команды для удаления или переименования (username)
+type TeamMemberRequest struct {
+	TeamName string `json:"team_name"`
+	UserID   string `json:"user_id"`
+	UserName string `json:"username,omitempty"`
+}
+
+type TeamMemberRemoveResponse struct {
+	TeamName      string                 `json:"team_name"`
+	UserID        string                 `json:"user_id"`
+	Reassignments []ReassignmentResponse `json:"reassignments,omitempty"`
+}
+
+type TeamSummary struct {
+	TeamName      string `json:"team_name"`
+	Members       int    `json:"members"`
+	ActiveMembers int    `json:"active_members"`
+	OpenPRs       int    `json:"open_pull_requests"`
+}
+
+type TeamListResponse struct {
+	Teams      []TeamSummary `json:"teams"`
+	NextCursor string        `json:"next_cursor,omitempty"` // пусто на последней странице
+}
+
+type TeamDeleteResponse struct {
+	TeamName string `json:"team_name"`
+	Mode     string `json:"mode"`
+	OpenPRs  int    `json:"open_pull_requests"` // OPEN PR команды на момент удаления
+}
+
+// TeamSettingsRequest - частичное обновление: не переданные поля сохраняют текущие значения
+type TeamSettingsRequest struct {
+	TeamName         string  `json:"team_name"`
+	MinReviewers     *int    `json:"min_reviewers,omitempty"`
+	MaxReviewers     *int    `json:"max_reviewers,omitempty"`
+	ReviewerStrategy *string `json:"reviewer_strategy,omitempty"`
+	AutoBackfill     *bool   `json:"auto_backfill,omitempty"`
+
+	MinApprovals             *int  `json:"min_approvals,omitempty"`
+	BlockOnChangesRequested  *bool `json:"block_on_changes_requested,omitempty"`
+	BlockOnNeedMoreReviewers *bool `json:"block_on_need_more_reviewers,omitempty"`
+}
+
+type TeamSettingsResponse struct {
+	TeamName         string `json:"team_name"`
+	MinReviewers     int    `json:"min_reviewers"`
+	MaxReviewers     int    `json:"max_reviewers"`
+	ReviewerStrategy string `json:"reviewer_strategy,omitempty"`
+	AutoBackfill     bool   `json:"auto_backfill"`
+
+	MinApprovals             int  `json:"min_approvals"`
+	BlockOnChangesRequested  bool `json:"block_on_changes_requested"`
+	BlockOnNeedMoreReviewers bool `json:"block_on_need_more_reviewers"`
+}
@@ -1,8 +1,10 @@
 package dto
 
+import "time"
+
 type UserRequest struct {
 	UserID   string `json:"user_id"`
-	TeamName string `json:"team_name"` // нужный параметр, без него невозможна выборка пользователей
+	TeamName string `json:"team_name"` // необязателен, если пользователь состоит ровно в одной команде
 	IsActive bool   `json:"is_active"`
 }
 
@@ -12,10 +14,102 @@ type UserResponse struct {
 }
 
 type UserFullResponse struct {
-	User UserResponse `json:"user"`
+	User          UserResponse           `json:"user"`
+	AssignedPRs   []string               `json:"assigned_pull_requests,omitempty"` // PR, куда пользователь добран после активации
+	Reassignments []ReassignmentResponse `json:"reassignments,omitempty"`          // ревью, снятые при деактивации
+}
+
+// UserPRsRequest - параметры /users/getReview
+type UserPRsRequest struct {
+	UserID   string
+	TeamName string // пусто - PR из всех команд пользователя
+	Role     string // reviewer, author или any (по умолчанию)
+	Status   string
+	Limit    string
+	Cursor   string
+}
+
+type UserPRResponse struct {
+	PRResponse
+	Role string `json:"role"` // роль пользователя в PR: author или reviewer
 }
 
 type UserPRsResponse struct {
-	UserID       string       `json:"user_id"`
-	PullRequests []PRResponse `json:"pull_requests"`
+	UserID       string           `json:"user_id"`
+	PullRequests []UserPRResponse `json:"pull_requests"`
+	NextCursor   string           `json:"next_cursor,omitempty"` // пусто на последней странице
+}
+
+type BulkDeactivateRequest struct {
+	TeamName string   `json:"team_name"`
+	UserIDs  []string `json:"user_ids"`
+}
+
+type BulkDeactivateResponse struct {
+	TeamName      string                 `json:"team_name"`
+	Deactivated   []string               `json:"deactivated"`
+	Reassignments []ReassignmentResponse `json:"reassignments"`
+}
+
+type UserTransferRequest struct {
+	UserID   string `json:"user_id"`
+	FromTeam string `json:"from_team"`
+	ToTeam   string `json:"to_team"`
+}
+
+type UserTransferResponse struct {
+	User          UserResponse           `json:"user"`
+	PreviousTeam  string                 `json:"previous_team"`
+	AssignedPRs   []string               `json:"assigned_pull_requests,omitempty"` // PR новой команды при auto_backfill
+	Reassignments []ReassignmentResponse `json:"reassignments,omitempty"`          // ревью в прежней команде
+}
+
+// UserHandoverRequest - без to_user_id ревью расходятся по команде стратегией команды
+type UserHandoverRequest struct {
+	UserID   string `json:"user_id"`
+	TeamName string `json:"team_name,omitempty"` // пусто - ревью во всех командах пользователя
+	ToUserID string `json:"to_user_id,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+type HandoverResultResponse struct {
+	PullRequestID     string             `json:"pull_request_id"`
+	TeamName          string             `json:"team_name"`
+	Status            string             `json:"status"` // reassigned или skipped
+	NewReviewerID     string             `json:"new_reviewer_id,omitempty"`
+	NeedMoreReviewers bool               `json:"need_more_reviewers"`
+	Error             *ErrorResponseBody `json:"error,omitempty"` // причина пропуска
+}
+
+type UserHandoverResponse struct {
+	UserID     string                   `json:"user_id"`
+	Reassigned int                      `json:"reassigned"`
+	Skipped    int                      `json:"skipped"`
+	Results    []HandoverResultResponse `json:"results"`
+}
+
+// AvailabilityRequest - период недоступности; id нужен только для обновления
+type AvailabilityRequest struct {
+	ID           int       `json:"id,omitempty"`
+	UserID       string    `json:"user_id"`
+	StartsAt     time.Time `json:"starts_at"`
+	EndsAt       time.Time `json:"ends_at"`
+	Reason       string    `json:"reason,omitempty"`
+	AutoHandover bool      `json:"auto_handover"`
+}
+
+type AvailabilityResponse struct {
+	ID           int        `json:"id"`
+	UserID       string     `json:"user_id"`
+	StartsAt     time.Time  `json:"starts_at"`
+	EndsAt       time.Time  `json:"ends_at"`
+	Reason       string     `json:"reason,omitempty"`
+	AutoHandover bool       `json:"auto_handover"`
+	HandedOverAt *time.Time `json:"handed_over_at,omitempty"`
+	Current      bool       `json:"current"` // период идёт сейчас
+}
+
+type AvailabilityListResponse struct {
+	UserID  string                 `json:"user_id"`
+	Periods []AvailabilityResponse `json:"periods"`
 }
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"pr-manage-service/internal/domain"
+	"pr-manage-service/internal/interfaces/dto"
+	"pr-manage-service/pkg/codes"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Auth - секреты, которыми обработчики проверяют инициатора запроса
+type Auth struct {
+	AdminToken  string
+	ActorSecret string // ключ подписи Actor-Signature; пустой - Actor-Id не принимается
+}
+
+// actorFromRequest определяет инициатора запроса: верный Admin-Token даёт права администратора,
+// а участник из заголовка Actor-Id принимается только с верной подписью Actor-Signature.
+// Неподписанный Actor-Id игнорируется. Возвращает false, если инициатора определить нельзя.
+func actorFromRequest(c *gin.Context, auth Auth) (domain.Actor, bool) {
+	actor := domain.Actor{Admin: c.GetHeader("Admin-Token") == auth.AdminToken}
+	if userID := c.GetHeader("Actor-Id"); auth.verifyActor(userID, c.GetHeader("Actor-Signature")) {
+		actor.UserID = userID
+	}
+	return actor, actor.Admin || actor.UserID != ""
+}
+
+// verifyActor проверяет подпись Actor-Signature: hex HMAC-SHA256 от user_id на ключе ActorSecret
+func (a Auth) verifyActor(userID, signature string) bool {
+	if a.ActorSecret == "" || userID == "" {
+		return false
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(got, signActor(a.ActorSecret, userID))
+}
+
+func signActor(secret, userID string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(userID))
+	return mac.Sum(nil)
+}
+
+func writeUnauthorized(c *gin.Context) {
+	c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+		Err: dto.ErrorResponseBody{
+			Code: codes.NOT_FOUND,
+			Msg:  "resource not found",
+		},
+	})
+}
+
+func writeForbidden(c *gin.Context, err error) {
+	c.JSON(http.StatusForbidden, dto.ErrorResponse{
+		Err: dto.ErrorResponseBody{
+			Code: codes.FORBIDDEN,
+			Msg:  err.Error(),
+		},
+	})
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/hex"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestActorFromRequest(t *testing.T) {
+	auth := Auth{AdminToken: "admin", ActorSecret: "secret"}
+	signed := hex.EncodeToString(signActor("secret", "u1"))
+
+	tests := []struct {
+		name      string
+		auth      Auth
+		headers   map[string]string
+		wantUser  string
+		wantAdmin bool
+		wantOK    bool
+	}{
+		{name: "anonymous", auth: auth},
+		{name: "admin", auth: auth, headers: map[string]string{"Admin-Token": "admin"}, wantAdmin: true, wantOK: true},
+		{name: "signed actor", auth: auth, headers: map[string]string{"Actor-Id": "u1", "Actor-Signature": signed}, wantUser: "u1", wantOK: true},
+		{name: "unsigned actor", auth: auth, headers: map[string]string{"Actor-Id": "u1"}},
+		{name: "signature of another user", auth: auth, headers: map[string]string{"Actor-Id": "u2", "Actor-Signature": signed}},
+		{name: "malformed signature", auth: auth, headers: map[string]string{"Actor-Id": "u1", "Actor-Signature": "zz"}},
+		{name: "no actor secret", auth: Auth{AdminToken: "admin"}, headers: map[string]string{"Actor-Id": "u1", "Actor-Signature": hex.EncodeToString(signActor("", "u1"))}},
+		{
+			name:      "admin with signed actor",
+			auth:      auth,
+			headers:   map[string]string{"Admin-Token": "admin", "Actor-Id": "u1", "Actor-Signature": signed},
+			wantUser:  "u1",
+			wantAdmin: true,
+			wantOK:    true,
+		},
+		{
+			name:      "admin with unsigned actor",
+			auth:      auth,
+			headers:   map[string]string{"Admin-Token": "admin", "Actor-Id": "u1"},
+			wantAdmin: true,
+			wantOK:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("POST", "/", nil)
+			for key, value := range tt.headers {
+				c.Request.Header.Set(key, value)
+			}
+
+			actor, ok := actorFromRequest(c, tt.auth)
+			if ok != tt.wantOK {
+				t.Errorf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if actor.UserID != tt.wantUser {
+				t.Errorf("UserID = %q, want %q", actor.UserID, tt.wantUser)
+			}
+			if actor.Admin != tt.wantAdmin {
+				t.Errorf("Admin = %v, want %v", actor.Admin, tt.wantAdmin)
+			}
+		})
+	}
+}
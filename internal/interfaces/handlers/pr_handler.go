@@ -13,11 +13,13 @@ import (
 
 type PrHandler struct {
 	usecase domain.PRService
+	auth    Auth
 }
 
-func NewPRHandler(ctx context.Context, repo domain.PRService) *PrHandler {
+func NewPRHandler(ctx context.Context, repo domain.PRService, auth Auth) *PrHandler {
 	prHandler := &PrHandler{
 		usecase: repo,
+		auth:    auth,
 	}
 	return prHandler
 }
@@ -29,7 +31,14 @@ func (h *PrHandler) CreateHandler(c *gin.Context) {
 		return
 	}
 	if resp, err := h.usecase.Create(&req); err != nil {
-		switch err.(type) {
+		switch v := err.(type) {
+		case *errs.DomainError:
+			c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Err: dto.ErrorResponseBody{
+					Code: v.Code,
+					Msg:  err.Error(),
+				},
+			})
 		case *errs.NotFoundError:
 			c.JSON(http.StatusNotFound, dto.ErrorResponse{
 				Err: dto.ErrorResponseBody{
@@ -56,13 +65,31 @@ func (h *PrHandler) CreateHandler(c *gin.Context) {
 }
 
 func (h *PrHandler) MergeHandler(c *gin.Context) {
+	// Мержить могут мейнтейнеры и лиды команды PR, администратор - любой PR
+	actor, ok := actorFromRequest(c, h.auth)
+	if !ok {
+		writeUnauthorized(c)
+		return
+	}
 	var mergeReq dto.PRCreateRequest
 	if err := c.BindJSON(&mergeReq); err != nil {
 		c.Status(http.StatusBadRequest)
 		return
 	}
-	if resp, err := h.usecase.Merge(&mergeReq); err != nil {
-		switch err.(type) {
+	if resp, err := h.usecase.Merge(&mergeReq, actor); err != nil {
+		switch v := err.(type) {
+		case *errs.ForbiddenError:
+			writeForbidden(c, err)
+			return
+		case *errs.DomainError:
+			c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Err: dto.ErrorResponseBody{
+					Code:    v.Code,
+					Msg:     err.Error(),
+					Details: v.Details,
+				},
+			})
+			return
 		case *errs.NotFoundError:
 			c.JSON(http.StatusNotFound, dto.ErrorResponse{
 				Err: dto.ErrorResponseBody{
@@ -81,16 +108,23 @@ func (h *PrHandler) MergeHandler(c *gin.Context) {
 }
 
 func (h *PrHandler) ReassignHandler(c *gin.Context) {
-	var req struct {
-		PrID     string `json:"pull_request_id"`
-		OldRevID string `json:"old_reviewer_id"`
-	}
+	var req dto.PRReassignRequest
 	if err := c.BindJSON(&req); err != nil {
 		c.Status(http.StatusBadRequest)
 		return
 	}
-	if resp, err := h.usecase.Reassign(req.PrID, req.OldRevID); err != nil {
+	// Запрос открытый: подписанный Actor-Id только попадает в историю PR, без него инициатор не записывается
+	actor, _ := actorFromRequest(c, h.auth)
+	if resp, err := h.usecase.Reassign(&req, actor); err != nil {
 		switch v := err.(type) {
+		case *errs.InvalidError:
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Err: dto.ErrorResponseBody{
+					Code: codes.INVALID_INPUT,
+					Msg:  err.Error(),
+				},
+			})
+			return
 		case *errs.DomainError:
 			c.JSON(http.StatusConflict, dto.ErrorResponse{
 				Err: dto.ErrorResponseBody{
@@ -115,3 +149,172 @@ func (h *PrHandler) ReassignHandler(c *gin.Context) {
 		return
 	}
 }
+
+func (h *PrHandler) ReviewHandler(c *gin.Context) {
+	actor, ok := actorFromRequest(c, h.auth)
+	if !ok {
+		writeUnauthorized(c)
+		return
+	}
+	var req dto.PRReviewRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if resp, err := h.usecase.Review(&req, actor); err != nil {
+		switch v := err.(type) {
+		case *errs.ForbiddenError:
+			writeForbidden(c, err)
+		case *errs.InvalidError:
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Err: dto.ErrorResponseBody{
+					Code: codes.INVALID_INPUT,
+					Msg:  err.Error(),
+				},
+			})
+		case *errs.DomainError:
+			c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Err: dto.ErrorResponseBody{
+					Code: v.Code,
+					Msg:  err.Error(),
+				},
+			})
+		case *errs.NotFoundError:
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Err: dto.ErrorResponseBody{
+					Code: codes.NOT_FOUND,
+					Msg:  err.Error(),
+				},
+			})
+		case *errs.InternalError:
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+	} else {
+		c.JSON(http.StatusOK, gin.H{`pr`: resp})
+	}
+}
+
+func (h *PrHandler) CloseHandler(c *gin.Context) {
+	var req dto.PRCreateRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	actor, _ := actorFromRequest(c, h.auth)
+	if resp, err := h.usecase.Close(req.PullRequestID, actor); err != nil {
+		writeStatusError(c, err)
+	} else {
+		c.JSON(http.StatusOK, gin.H{`pr`: resp})
+	}
+}
+
+func (h *PrHandler) ReopenHandler(c *gin.Context) {
+	var req dto.PRCreateRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	actor, _ := actorFromRequest(c, h.auth)
+	if resp, err := h.usecase.Reopen(req.PullRequestID, actor); err != nil {
+		writeStatusError(c, err)
+	} else {
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+func (h *PrHandler) ReadyHandler(c *gin.Context) {
+	var req dto.PRCreateRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	actor, _ := actorFromRequest(c, h.auth)
+	if resp, err := h.usecase.Ready(req.PullRequestID, actor); err != nil {
+		writeStatusError(c, err)
+	} else {
+		c.JSON(http.StatusOK, gin.H{`pr`: resp})
+	}
+}
+
+func (h *PrHandler) GetHandler(c *gin.Context) {
+	prID, has := c.GetQuery("pull_request_id")
+	if !has {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Err: dto.ErrorResponseBody{
+				Code: codes.INVALID_INPUT,
+				Msg:  "indefined 'pull_request_id' query var",
+			},
+		})
+		return
+	}
+	if resp, err := h.usecase.Get(prID); err != nil {
+		writeStatusError(c, err)
+	} else {
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+func (h *PrHandler) HistoryHandler(c *gin.Context) {
+	prID, has := c.GetQuery("pull_request_id")
+	if !has {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Err: dto.ErrorResponseBody{
+				Code: codes.INVALID_INPUT,
+				Msg:  "indefined 'pull_request_id' query var",
+			},
+		})
+		return
+	}
+	if resp, err := h.usecase.History(prID); err != nil {
+		writeStatusError(c, err)
+	} else {
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+func (h *PrHandler) ListHandler(c *gin.Context) {
+	var req dto.PRListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if resp, err := h.usecase.List(&req); err != nil {
+		switch err.(type) {
+		case *errs.InvalidError:
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Err: dto.ErrorResponseBody{
+					Code: codes.INVALID_INPUT,
+					Msg:  err.Error(),
+				},
+			})
+		default:
+			c.Status(http.StatusInternalServerError)
+		}
+	} else {
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// writeStatusError отвечает на ошибки операций смены статуса PR
+func writeStatusError(c *gin.Context, err error) {
+	switch v := err.(type) {
+	case *errs.DomainError:
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Err: dto.ErrorResponseBody{
+				Code:    v.Code,
+				Msg:     err.Error(),
+				Details: v.Details,
+			},
+		})
+	case *errs.NotFoundError:
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Err: dto.ErrorResponseBody{
+				Code: codes.NOT_FOUND,
+				Msg:  err.Error(),
+			},
+		})
+	default:
+		c.Status(http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+	"pr-manage-service/internal/domain"
+	"pr-manage-service/internal/interfaces/dto"
+	"pr-manage-service/pkg/codes"
+	"pr-manage-service/pkg/errs"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StatsHandler struct {
+	usecase domain.StatsService
+}
+
+func NewStatsHandler(usecase domain.StatsService) *StatsHandler {
+	return &StatsHandler{
+		usecase: usecase,
+	}
+}
+
+func (h *StatsHandler) GetStatsHandler(c *gin.Context) {
+	if stats, err := h.usecase.GetStats(c.Query("team_name"), c.Query("from"), c.Query("to")); err != nil {
+		switch err.(type) {
+		case *errs.InvalidError:
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Err: dto.ErrorResponseBody{
+					Code: codes.INVALID_INPUT,
+					Msg:  err.Error(),
+				},
+			})
+		case *errs.InternalError:
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+	} else {
+		c.JSON(http.StatusOK, stats)
+	}
+}
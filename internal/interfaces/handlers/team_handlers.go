@@ -6,17 +6,20 @@ import (
 	"pr-manage-service/internal/interfaces/dto"
 	"pr-manage-service/pkg/codes"
 	"pr-manage-service/pkg/errs"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
 type TeamHandler struct {
 	usecase domain.TeamService
+	auth    Auth
 }
 
-func NewTeamHandler(usecase domain.TeamService) *TeamHandler {
+func NewTeamHandler(usecase domain.TeamService, auth Auth) *TeamHandler {
 	return &TeamHandler{
 		usecase: usecase,
+		auth:    auth,
 	}
 }
 
@@ -26,8 +29,12 @@ func (h *TeamHandler) AddTeamHandler(c *gin.Context) {
 		c.Status(http.StatusBadRequest)
 		return
 	}
-	if err := h.usecase.AddTeam(&team); err != nil {
-		switch err.(type) {
+	// Инициатор нужен только для ролей участников
+	actor, _ := actorFromRequest(c, h.auth)
+	if err := h.usecase.AddTeam(&team, actor); err != nil {
+		switch v := err.(type) {
+		case *errs.ForbiddenError:
+			writeForbidden(c, err)
 		case *errs.InvalidError:
 			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 				Err: dto.ErrorResponseBody{
@@ -39,6 +46,13 @@ func (h *TeamHandler) AddTeamHandler(c *gin.Context) {
 		case *errs.InternalError:
 			c.Status(http.StatusInternalServerError)
 			return
+		case *errs.DomainError:
+			c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Err: dto.ErrorResponseBody{
+					Code: v.Code,
+					Msg:  err.Error(),
+				},
+			})
 		// 400
 		case *errs.AlreadyExistsError:
 			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
@@ -91,3 +105,198 @@ func (h *TeamHandler) GetTeamHandler(c *gin.Context) {
 		return
 	}
 }
+
+func (h *TeamHandler) GetSettingsHandler(c *gin.Context) {
+	queryTeamName, has := c.GetQuery("team_name")
+	if !has {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Err: dto.ErrorResponseBody{
+				Code: codes.INVALID_INPUT,
+				Msg:  "indefined 'team_name' query var",
+			},
+		})
+		return
+	}
+	if settings, err := h.usecase.GetSettings(queryTeamName); err != nil {
+		switch err.(type) {
+		case *errs.InternalError:
+			c.Status(http.StatusInternalServerError)
+			return
+		case *errs.NotFoundError:
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Err: dto.ErrorResponseBody{
+					Code: codes.NOT_FOUND,
+					Msg:  err.Error(),
+				},
+			})
+		}
+	} else {
+		c.JSON(http.StatusOK, settings)
+	}
+}
+
+func (h *TeamHandler) UpdateSettingsHandler(c *gin.Context) {
+	// Политику ревью и merge меняет лид команды или администратор
+	actor, ok := actorFromRequest(c, h.auth)
+	if !ok {
+		writeUnauthorized(c)
+		return
+	}
+	var req dto.TeamSettingsRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if settings, err := h.usecase.UpdateSettings(&req, actor); err != nil {
+		switch err.(type) {
+		case *errs.ForbiddenError:
+			writeForbidden(c, err)
+		case *errs.InvalidError:
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Err: dto.ErrorResponseBody{
+					Code: codes.INVALID_INPUT,
+					Msg:  err.Error(),
+				},
+			})
+		case *errs.InternalError:
+			c.Status(http.StatusInternalServerError)
+			return
+		case *errs.NotFoundError:
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Err: dto.ErrorResponseBody{
+					Code: codes.NOT_FOUND,
+					Msg:  err.Error(),
+				},
+			})
+		}
+	} else {
+		c.JSON(http.StatusOK, settings)
+	}
+}
+
+func (h *TeamHandler) AddMembersHandler(c *gin.Context) {
+	var req dto.TeamRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	// Роли и mandatory_reviewer меняет только лид команды или администратор
+	actor, _ := actorFromRequest(c, h.auth)
+	if resp, err := h.usecase.AddMembers(&req, actor); err != nil {
+		writeTeamError(c, err)
+	} else {
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+func (h *TeamHandler) RemoveMemberHandler(c *gin.Context) {
+	// Состав команды меняет лид команды или администратор
+	actor, ok := actorFromRequest(c, h.auth)
+	if !ok {
+		writeUnauthorized(c)
+		return
+	}
+	var req dto.TeamMemberRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if resp, err := h.usecase.RemoveMember(&req, actor); err != nil {
+		writeTeamError(c, err)
+	} else {
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+func (h *TeamHandler) RenameMemberHandler(c *gin.Context) {
+	actor, ok := actorFromRequest(c, h.auth)
+	if !ok {
+		writeUnauthorized(c)
+		return
+	}
+	var req dto.TeamMemberRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if user, err := h.usecase.RenameMember(&req, actor); err != nil {
+		writeTeamError(c, err)
+	} else {
+		c.JSON(http.StatusOK, gin.H{`user`: user})
+	}
+}
+
+func (h *TeamHandler) DeleteTeamHandler(c *gin.Context) {
+	// Жёсткое удаление уносит участников, PR и историю, поэтому удаление только для администратора
+	if c.GetHeader("Admin-Token") != h.auth.AdminToken {
+		writeUnauthorized(c)
+		return
+	}
+	teamName, has := c.GetQuery("team_name")
+	if !has {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Err: dto.ErrorResponseBody{
+				Code: codes.INVALID_INPUT,
+				Msg:  "indefined 'team_name' query var",
+			},
+		})
+		return
+	}
+	force := false
+	if value := c.Query("force"); value != "" {
+		var err error
+		if force, err = strconv.ParseBool(value); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Err: dto.ErrorResponseBody{
+					Code: codes.INVALID_INPUT,
+					Msg:  "'force' must be true or false",
+				},
+			})
+			return
+		}
+	}
+	if resp, err := h.usecase.DeleteTeam(teamName, c.Query("mode"), force); err != nil {
+		writeTeamError(c, err)
+	} else {
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+func (h *TeamHandler) ListTeamsHandler(c *gin.Context) {
+	if resp, err := h.usecase.ListTeams(c.Query("prefix"), c.Query("limit"), c.Query("cursor")); err != nil {
+		writeTeamError(c, err)
+	} else {
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// writeTeamError отвечает на ошибки операций с участниками команды
+func writeTeamError(c *gin.Context, err error) {
+	switch v := err.(type) {
+	case *errs.ForbiddenError:
+		writeForbidden(c, err)
+	case *errs.DomainError:
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Err: dto.ErrorResponseBody{
+				Code: v.Code,
+				Msg:  err.Error(),
+			},
+		})
+	case *errs.InvalidError:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Err: dto.ErrorResponseBody{
+				Code: codes.INVALID_INPUT,
+				Msg:  err.Error(),
+			},
+		})
+	case *errs.NotFoundError:
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Err: dto.ErrorResponseBody{
+				Code: codes.NOT_FOUND,
+				Msg:  err.Error(),
+			},
+		})
+	default:
+		c.Status(http.StatusInternalServerError)
+	}
+}
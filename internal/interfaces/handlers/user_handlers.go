@@ -11,25 +11,22 @@ import (
 )
 
 type UserHandler struct {
-	usecase    domain.UserService
-	adminToken string
+	usecase domain.UserService
+	auth    Auth
 }
 
-func NewUserHandler(usecase domain.UserService, adminToken string) *UserHandler {
+func NewUserHandler(usecase domain.UserService, auth Auth) *UserHandler {
 	return &UserHandler{
-		usecase:    usecase,
-		adminToken: adminToken,
+		usecase: usecase,
+		auth:    auth,
 	}
 }
 
 func (h *UserHandler) SetIsActiveHandler(c *gin.Context) {
-	if c.GetHeader("Admin-Token") != h.adminToken {
-		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
-			Err: dto.ErrorResponseBody{
-				Code: codes.NOT_FOUND,
-				Msg:  "resource not found",
-			},
-		})
+	// Лид меняет активность участников своей команды, администратор - любой
+	actor, ok := actorFromRequest(c, h.auth)
+	if !ok {
+		writeUnauthorized(c)
 		return
 	}
 	var user dto.UserRequest
@@ -37,8 +34,24 @@ func (h *UserHandler) SetIsActiveHandler(c *gin.Context) {
 		c.Status(http.StatusBadRequest)
 		return
 	}
-	if username, err := h.usecase.SetIsActive(user.TeamName, user.UserID, user.IsActive); err != nil {
-		switch err.(type) {
+	if resp, err := h.usecase.SetIsActive(user.TeamName, user.UserID, user.IsActive, actor); err != nil {
+		switch v := err.(type) {
+		case *errs.ForbiddenError:
+			writeForbidden(c, err)
+		case *errs.DomainError:
+			c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Err: dto.ErrorResponseBody{
+					Code: v.Code,
+					Msg:  err.Error(),
+				},
+			})
+		case *errs.InvalidError:
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Err: dto.ErrorResponseBody{
+					Code: codes.INVALID_INPUT,
+					Msg:  err.Error(),
+				},
+			})
 		case *errs.InternalError:
 			c.Status(http.StatusInternalServerError)
 			return
@@ -51,29 +64,14 @@ func (h *UserHandler) SetIsActiveHandler(c *gin.Context) {
 			})
 		}
 	} else {
-		c.JSON(http.StatusOK, dto.UserFullResponse{
-			User: dto.UserResponse{
-				UserRequest: &user,
-				UserName:    username,
-			},
-		})
+		c.JSON(http.StatusOK, resp)
 		return
 	}
 }
 
 func (h *UserHandler) GetReviewHandler(c *gin.Context) {
-	var teamName, userID string
-	teamName, has := c.GetQuery("team_name")
-	if !has {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Err: dto.ErrorResponseBody{
-				Code: codes.INVALID_INPUT,
-				Msg:  "indefined 'team_name' query var",
-			},
-		})
-		return
-	}
-	userID, has = c.GetQuery("user_id")
+	// team_name необязателен: без него выдаются PR из всех команд пользователя
+	userID, has := c.GetQuery("user_id")
 	if !has {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Err: dto.ErrorResponseBody{
@@ -83,8 +81,24 @@ func (h *UserHandler) GetReviewHandler(c *gin.Context) {
 		})
 		return
 	}
-	if resp, err := h.usecase.GetReview(teamName, userID); err != nil {
+	req := dto.UserPRsRequest{
+		UserID:   userID,
+		TeamName: c.Query("team_name"),
+		Role:     c.Query("role"),
+		Status:   c.Query("status"),
+		Limit:    c.Query("limit"),
+		Cursor:   c.Query("cursor"),
+	}
+	if resp, err := h.usecase.GetReview(&req); err != nil {
 		switch err.(type) {
+		case *errs.InvalidError:
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Err: dto.ErrorResponseBody{
+					Code: codes.INVALID_INPUT,
+					Msg:  err.Error(),
+				},
+			})
+			return
 		case *errs.InternalError:
 			c.Status(http.StatusInternalServerError)
 			return
@@ -100,3 +114,241 @@ func (h *UserHandler) GetReviewHandler(c *gin.Context) {
 		c.JSON(http.StatusOK, resp)
 	}
 }
+
+func (h *UserHandler) BulkDeactivateHandler(c *gin.Context) {
+	actor, ok := actorFromRequest(c, h.auth)
+	if !ok {
+		writeUnauthorized(c)
+		return
+	}
+	var req dto.BulkDeactivateRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if resp, err := h.usecase.BulkDeactivate(&req, actor); err != nil {
+		switch v := err.(type) {
+		case *errs.ForbiddenError:
+			writeForbidden(c, err)
+		case *errs.DomainError:
+			c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Err: dto.ErrorResponseBody{
+					Code: v.Code,
+					Msg:  err.Error(),
+				},
+			})
+		case *errs.InvalidError:
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Err: dto.ErrorResponseBody{
+					Code: codes.INVALID_INPUT,
+					Msg:  err.Error(),
+				},
+			})
+		case *errs.InternalError:
+			c.Status(http.StatusInternalServerError)
+			return
+		case *errs.NotFoundError:
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Err: dto.ErrorResponseBody{
+					Code: codes.NOT_FOUND,
+					Msg:  err.Error(),
+				},
+			})
+		}
+	} else {
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+func (h *UserHandler) TransferHandler(c *gin.Context) {
+	if c.GetHeader("Admin-Token") != h.auth.AdminToken {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Err: dto.ErrorResponseBody{
+				Code: codes.NOT_FOUND,
+				Msg:  "resource not found",
+			},
+		})
+		return
+	}
+	var req dto.UserTransferRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if resp, err := h.usecase.Transfer(&req); err != nil {
+		switch v := err.(type) {
+		case *errs.InvalidError:
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Err: dto.ErrorResponseBody{
+					Code: codes.INVALID_INPUT,
+					Msg:  err.Error(),
+				},
+			})
+		case *errs.DomainError:
+			c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Err: dto.ErrorResponseBody{
+					Code: v.Code,
+					Msg:  err.Error(),
+				},
+			})
+		case *errs.NotFoundError:
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Err: dto.ErrorResponseBody{
+					Code: codes.NOT_FOUND,
+					Msg:  err.Error(),
+				},
+			})
+		default:
+			c.Status(http.StatusInternalServerError)
+		}
+	} else {
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+func (h *UserHandler) HandoverHandler(c *gin.Context) {
+	// Свои ревью передаёт сам пользователь (подписанный Actor-Id), чужие - лид команды или администратор
+	actor, ok := actorFromRequest(c, h.auth)
+	if !ok {
+		writeUnauthorized(c)
+		return
+	}
+	var req dto.UserHandoverRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if resp, err := h.usecase.Handover(&req, actor); err != nil {
+		switch v := err.(type) {
+		case *errs.ForbiddenError:
+			writeForbidden(c, err)
+		case *errs.InvalidError:
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Err: dto.ErrorResponseBody{
+					Code: codes.INVALID_INPUT,
+					Msg:  err.Error(),
+				},
+			})
+		case *errs.DomainError:
+			c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Err: dto.ErrorResponseBody{
+					Code: v.Code,
+					Msg:  err.Error(),
+				},
+			})
+		case *errs.NotFoundError:
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Err: dto.ErrorResponseBody{
+					Code: codes.NOT_FOUND,
+					Msg:  err.Error(),
+				},
+			})
+		default:
+			c.Status(http.StatusInternalServerError)
+		}
+	} else {
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+func (h *UserHandler) AddAvailabilityHandler(c *gin.Context) {
+	// Периодами недоступности управляет сам пользователь (подписанный Actor-Id), лид его команды или администратор
+	actor, ok := actorFromRequest(c, h.auth)
+	if !ok {
+		writeUnauthorized(c)
+		return
+	}
+	var req dto.AvailabilityRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if resp, err := h.usecase.AddAvailability(&req, actor); err != nil {
+		writeUserError(c, err)
+	} else {
+		c.JSON(http.StatusCreated, resp)
+	}
+}
+
+func (h *UserHandler) ListAvailabilityHandler(c *gin.Context) {
+	userID, has := c.GetQuery("user_id")
+	if !has {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Err: dto.ErrorResponseBody{
+				Code: codes.INVALID_INPUT,
+				Msg:  "indefined 'user_id' query var",
+			},
+		})
+		return
+	}
+	if resp, err := h.usecase.ListAvailability(userID); err != nil {
+		writeUserError(c, err)
+	} else {
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+func (h *UserHandler) UpdateAvailabilityHandler(c *gin.Context) {
+	actor, ok := actorFromRequest(c, h.auth)
+	if !ok {
+		writeUnauthorized(c)
+		return
+	}
+	var req dto.AvailabilityRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if resp, err := h.usecase.UpdateAvailability(&req, actor); err != nil {
+		writeUserError(c, err)
+	} else {
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+func (h *UserHandler) DeleteAvailabilityHandler(c *gin.Context) {
+	actor, ok := actorFromRequest(c, h.auth)
+	if !ok {
+		writeUnauthorized(c)
+		return
+	}
+	id, has := c.GetQuery("id")
+	if !has {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Err: dto.ErrorResponseBody{
+				Code: codes.INVALID_INPUT,
+				Msg:  "indefined 'id' query var",
+			},
+		})
+		return
+	}
+	if resp, err := h.usecase.DeleteAvailability(id, actor); err != nil {
+		writeUserError(c, err)
+	} else {
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// writeUserError отвечает на ошибки операций с периодами недоступности
+func writeUserError(c *gin.Context, err error) {
+	switch err.(type) {
+	case *errs.ForbiddenError:
+		writeForbidden(c, err)
+	case *errs.InvalidError:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Err: dto.ErrorResponseBody{
+				Code: codes.INVALID_INPUT,
+				Msg:  err.Error(),
+			},
+		})
+	case *errs.NotFoundError:
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Err: dto.ErrorResponseBody{
+				Code: codes.NOT_FOUND,
+				Msg:  err.Error(),
+			},
+		})
+	default:
+		c.Status(http.StatusInternalServerError)
+	}
+}
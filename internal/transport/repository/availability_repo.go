@@ -0,0 +1,298 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"pr-manage-service/internal/domain"
+	"pr-manage-service/pkg/errs"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+)
+
+const availabilityColumns = `id, user_id, starts_at, ends_at, COALESCE(reason, ''), auto_handover, handed_over_at`
+
+// AddAvailability implements domain.UserRepository.
+func (u *userRepository) AddAvailability(period *domain.Availability, actor domain.Actor) (*domain.Availability, error) {
+	reqCtx, cancel := context.WithTimeout(u.ctx, u.rtimeout)
+	defer cancel()
+
+	tx, err := u.pool.Begin(reqCtx)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	defer tx.Rollback(reqCtx)
+
+	var exists bool
+	if err := tx.QueryRow(reqCtx,
+		`SELECT EXISTS (SELECT 1 FROM users WHERE user_id = $1)`, period.UserID).Scan(&exists); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	if !exists {
+		return nil, &errs.NotFoundError{Domain: "user"}
+	}
+	if err := authorizeUser(reqCtx, tx, period.UserID, actor); err != nil {
+		return nil, prLoadError(err)
+	}
+
+	created, err := scanAvailability(tx.QueryRow(reqCtx, `
+        INSERT INTO user_availability (user_id, starts_at, ends_at, reason, auto_handover)
+        VALUES ($1, $2, $3, NULLIF($4, ''), $5)
+        RETURNING `+availabilityColumns,
+		period.UserID, period.StartsAt, period.EndsAt, period.Reason, period.AutoHandover))
+	if err != nil {
+		logrus.Error(logPrefix, "(insert) error:", err.Error())
+		return nil, &errs.InternalError{}
+	}
+
+	if err := tx.Commit(reqCtx); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	return created, nil
+}
+
+// ListAvailability implements domain.UserRepository.
+func (u *userRepository) ListAvailability(userID string) ([]domain.Availability, error) {
+	reqCtx, cancel := context.WithTimeout(u.ctx, u.rtimeout)
+	defer cancel()
+
+	var exists bool
+	if err := u.pool.QueryRow(reqCtx,
+		`SELECT EXISTS (SELECT 1 FROM users WHERE user_id = $1)`, userID).Scan(&exists); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	if !exists {
+		return nil, &errs.NotFoundError{Domain: "user"}
+	}
+
+	rows, err := u.pool.Query(reqCtx, `
+        SELECT `+availabilityColumns+`
+        FROM user_availability
+        WHERE user_id = $1
+        ORDER BY starts_at, id
+    `, userID)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	defer rows.Close()
+
+	periods := []domain.Availability{}
+	for rows.Next() {
+		period, err := scanAvailability(rows)
+		if err != nil {
+			logrus.Error(logPrefix, err.Error())
+			return nil, &errs.InternalError{}
+		}
+		periods = append(periods, *period)
+	}
+	if err := rows.Err(); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	return periods, nil
+}
+
+// UpdateAvailability implements domain.UserRepository.
+// Если начало периода перенесено в будущее, автоматическая передача ревью выполнится заново.
+func (u *userRepository) UpdateAvailability(period *domain.Availability, actor domain.Actor) (*domain.Availability, error) {
+	reqCtx, cancel := context.WithTimeout(u.ctx, u.rtimeout)
+	defer cancel()
+
+	tx, err := u.pool.Begin(reqCtx)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	defer tx.Rollback(reqCtx)
+
+	if err := lockAvailability(reqCtx, tx, period.ID, actor); err != nil {
+		return nil, err
+	}
+	updated, err := scanAvailability(tx.QueryRow(reqCtx, `
+        UPDATE user_availability
+        SET starts_at = $2,
+            ends_at = $3,
+            reason = NULLIF($4, ''),
+            auto_handover = $5,
+            handed_over_at = CASE WHEN $2 > now() THEN NULL ELSE handed_over_at END
+        WHERE id = $1
+        RETURNING `+availabilityColumns,
+		period.ID, period.StartsAt, period.EndsAt, period.Reason, period.AutoHandover))
+	if err != nil {
+		logrus.Error(logPrefix, "(update) error:", err.Error())
+		return nil, &errs.InternalError{}
+	}
+
+	if err := tx.Commit(reqCtx); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	return updated, nil
+}
+
+// DeleteAvailability implements domain.UserRepository.
+func (u *userRepository) DeleteAvailability(id int, actor domain.Actor) (*domain.Availability, error) {
+	reqCtx, cancel := context.WithTimeout(u.ctx, u.rtimeout)
+	defer cancel()
+
+	tx, err := u.pool.Begin(reqCtx)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	defer tx.Rollback(reqCtx)
+
+	if err := lockAvailability(reqCtx, tx, id, actor); err != nil {
+		return nil, err
+	}
+	deleted, err := scanAvailability(tx.QueryRow(reqCtx,
+		`DELETE FROM user_availability WHERE id = $1 RETURNING `+availabilityColumns, id))
+	if err != nil {
+		logrus.Error(logPrefix, "(delete) error:", err.Error())
+		return nil, &errs.InternalError{}
+	}
+
+	if err := tx.Commit(reqCtx); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	return deleted, nil
+}
+
+// StartHandovers implements domain.UserRepository.
+func (u *userRepository) StartHandovers(selectors *domain.ReviewerSelectors) (int, error) {
+	handedOver := 0
+	for {
+		started, err := u.startNextHandover(selectors)
+		if err != nil {
+			return handedOver, err
+		}
+		if !started {
+			return handedOver, nil
+		}
+		handedOver++
+	}
+}
+
+// startNextHandover передаёт ревью по одному начавшемуся периоду с auto_handover и отмечает его.
+// Возвращает false, если таких периодов не осталось. Параллельные экземпляры сервиса
+// разбирают разные периоды благодаря SKIP LOCKED.
+func (u *userRepository) startNextHandover(selectors *domain.ReviewerSelectors) (bool, error) {
+	reqCtx, cancel := context.WithTimeout(u.ctx, u.rtimeout)
+	defer cancel()
+
+	tx, err := u.pool.Begin(reqCtx)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return false, &errs.InternalError{}
+	}
+	defer tx.Rollback(reqCtx)
+
+	period, err := scanAvailability(tx.QueryRow(reqCtx, `
+        SELECT `+availabilityColumns+`
+        FROM user_availability
+        WHERE auto_handover AND handed_over_at IS NULL
+          AND starts_at <= now() AND ends_at > now()
+        ORDER BY starts_at, id
+        LIMIT 1
+        FOR UPDATE SKIP LOCKED
+    `))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return false, &errs.InternalError{}
+	}
+
+	// Передача идёт в savepoint: если она невозможна (например, пользователь исключён из всех команд),
+	// период всё равно отмечается, чтобы не повторять её на каждом тике. Архивные команды handoverUser
+	// пропускает сам, не отменяя передачу в остальных командах пользователя.
+	handover, err := tx.Begin(reqCtx)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return false, &errs.InternalError{}
+	}
+	_, err = handoverUser(reqCtx, handover, period.UserID, "", "", period.Reason, domain.Actor{Admin: true}, selectors)
+	var (
+		domainErr   *errs.DomainError
+		notFoundErr *errs.NotFoundError
+	)
+	switch {
+	case err == nil:
+		if err := handover.Commit(reqCtx); err != nil {
+			logrus.Error(logPrefix, err.Error())
+			return false, &errs.InternalError{}
+		}
+	case errors.As(err, &domainErr), errors.As(err, &notFoundErr):
+		handover.Rollback(reqCtx)
+		logrus.Warn("(auto handover) user ", period.UserID, " skipped: ", err.Error())
+	default:
+		return false, err
+	}
+
+	if _, err := tx.Exec(reqCtx,
+		`UPDATE user_availability SET handed_over_at = now() WHERE id = $1`, period.ID); err != nil {
+		logrus.Error(logPrefix, "(mark handed over) error:", err.Error())
+		return false, &errs.InternalError{}
+	}
+	if err := tx.Commit(reqCtx); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return false, &errs.InternalError{}
+	}
+	return true, nil
+}
+
+// lockAvailability блокирует период до конца транзакции и проверяет права инициатора на него
+func lockAvailability(ctx context.Context, tx pgx.Tx, id int, actor domain.Actor) error {
+	var userID string
+	err := tx.QueryRow(ctx,
+		`SELECT user_id FROM user_availability WHERE id = $1 FOR UPDATE`, id).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &errs.NotFoundError{Domain: "availability period"}
+	}
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return &errs.InternalError{}
+	}
+	if err := authorizeUser(ctx, tx, userID, actor); err != nil {
+		return prLoadError(err)
+	}
+	return nil
+}
+
+// authorizeUser пропускает самого пользователя (по подписанному Actor-Id), активного лида любой из его команд
+// и администратора
+func authorizeUser(ctx context.Context, tx pgx.Tx, userID string, actor domain.Actor) error {
+	if actor.Admin || actor.Is(userID) {
+		return nil
+	}
+	var allowed bool
+	if err := tx.QueryRow(ctx, `
+        SELECT EXISTS (
+            SELECT 1 FROM team_members lead
+            JOIN team_members m ON m.team_name = lead.team_name
+            WHERE m.user_id = $1 AND lead.user_id = $2 AND lead.is_active = true AND lead.role = $3
+        )
+    `, userID, actor.UserID, string(domain.RoleLead)).Scan(&allowed); err != nil {
+		return err
+	}
+	if !allowed {
+		return &errs.ForbiddenError{Desc: "only the user or a lead of their team can manage their availability"}
+	}
+	return nil
+}
+
+func scanAvailability(row pgx.Row) (*domain.Availability, error) {
+	period := &domain.Availability{}
+	if err := row.Scan(&period.ID, &period.UserID, &period.StartsAt, &period.EndsAt,
+		&period.Reason, &period.AutoHandover, &period.HandedOverAt); err != nil {
+		return nil, err
+	}
+	return period, nil
+}
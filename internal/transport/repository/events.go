@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"pr-manage-service/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// recordEvent дописывает событие в историю PR внутри транзакции изменения
+func recordEvent(ctx context.Context, tx pgx.Tx, event domain.PREvent) error {
+	_, err := tx.Exec(ctx, `
+        INSERT INTO pr_events (pr_id, type, actor_id, reviewer_id, new_reviewer_id, details, reason)
+        VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, ''))
+    `, event.PrID, string(event.Type), event.ActorID, event.ReviewerID, event.NewReviewerID, event.Details, event.Reason)
+	return err
+}
+
+// recordReassignment записывает замену ревьювера или его снятие, если замены не нашлось
+func recordReassignment(ctx context.Context, tx pgx.Tx, actorID, reason string, r domain.Reassignment) error {
+	return recordEvent(ctx, tx, reassignmentEvent(actorID, reason, r))
+}
+
+// recordReassignments записывает пачку замен одним запросом, сохраняя их порядок в истории
+func recordReassignments(ctx context.Context, tx pgx.Tx, actorID, reason string, reassignments []domain.Reassignment) error {
+	if len(reassignments) == 0 {
+		return nil
+	}
+	prIDs := make([]string, len(reassignments))
+	types := make([]string, len(reassignments))
+	reviewerIDs := make([]string, len(reassignments))
+	newReviewerIDs := make([]string, len(reassignments))
+	for index, r := range reassignments {
+		event := reassignmentEvent(actorID, reason, r)
+		prIDs[index] = event.PrID
+		types[index] = string(event.Type)
+		reviewerIDs[index] = event.ReviewerID
+		newReviewerIDs[index] = event.NewReviewerID
+	}
+	_, err := tx.Exec(ctx, `
+        INSERT INTO pr_events (pr_id, type, actor_id, reviewer_id, new_reviewer_id, reason)
+        SELECT e.pr_id, e.type, NULLIF($5, ''), NULLIF(e.reviewer_id, ''), NULLIF(e.new_reviewer_id, ''), NULLIF($6, '')
+        FROM unnest($1::text[], $2::text[], $3::text[], $4::text[])
+             WITH ORDINALITY AS e(pr_id, type, reviewer_id, new_reviewer_id, n)
+        ORDER BY e.n
+    `, prIDs, types, reviewerIDs, newReviewerIDs, actorID, reason)
+	return err
+}
+
+// reassignmentEvent - событие замены ревьювера; без замены это снятие ревьювера
+func reassignmentEvent(actorID, reason string, r domain.Reassignment) domain.PREvent {
+	event := domain.PREvent{
+		PrID:          r.PrID,
+		Type:          domain.EventReviewerRemoved,
+		ActorID:       actorID,
+		ReviewerID:    r.OldReviewerID,
+		NewReviewerID: r.NewReviewerID,
+		Reason:        reason,
+	}
+	if r.NewReviewerID != "" {
+		event.Type = domain.EventReviewerReplaced
+	}
+	return event
+}
+
+// recordAssignments записывает назначение ревьюверов на PR
+func recordAssignments(ctx context.Context, tx pgx.Tx, prID, actorID string, reviewers []reviewer) error {
+	for _, r := range reviewers {
+		if err := recordEvent(ctx, tx, domain.PREvent{
+			PrID:       prID,
+			Type:       domain.EventReviewerAssigned,
+			ActorID:    actorID,
+			ReviewerID: r.userID,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prHistory возвращает историю PR в порядке появления событий
+func prHistory(ctx context.Context, tx pgx.Tx, prID string) ([]domain.PREvent, error) {
+	rows, err := tx.Query(ctx, `
+        SELECT type, COALESCE(actor_id, ''), COALESCE(reviewer_id, ''), COALESCE(new_reviewer_id, ''),
+               COALESCE(details, ''), COALESCE(reason, ''), created_at
+        FROM pr_events
+        WHERE pr_id = $1
+        ORDER BY created_at, id
+    `, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []domain.PREvent
+	for rows.Next() {
+		event := domain.PREvent{PrID: prID}
+		var eventType string
+		if err := rows.Scan(&eventType, &event.ActorID, &event.ReviewerID, &event.NewReviewerID,
+			&event.Details, &event.Reason, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		event.Type = domain.PREventType(eventType)
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
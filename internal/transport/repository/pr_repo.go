@@ -29,7 +29,7 @@ func NewPullRequestRepository(ctx context.Context, pool *pgxpool.Pool, rtimeout
 }
 
 // CreateNewPR implements domain.PRRepository.
-func (r *PullRequestRepository) CreateNewPR(pr *domain.PullRequest) (assigned_reviewers []string, err error) {
+func (r *PullRequestRepository) CreateNewPR(pr *domain.PullRequest, selectors *domain.ReviewerSelectors) (states []domain.ReviewerState, err error) {
 	reqCtx, cancel := context.WithTimeout(r.ctx, r.rtimeout)
 	defer cancel()
 
@@ -43,7 +43,7 @@ func (r *PullRequestRepository) CreateNewPR(pr *domain.PullRequest) (assigned_re
 	// Получаем internal author_id (serial) по user_id (varchar)
 	var authorInternalID int
 	if err := tx.QueryRow(reqCtx,
-		`SELECT id FROM users WHERE user_id=$1 AND team_name=$2 AND is_active=true`,
+		`SELECT id FROM team_members WHERE user_id=$1 AND team_name=$2 AND is_active=true`,
 		pr.AuthorID, pr.TeamName).Scan(&authorInternalID); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, &errs.NotFoundError{
@@ -54,46 +54,51 @@ func (r *PullRequestRepository) CreateNewPR(pr *domain.PullRequest) (assigned_re
 		return nil, &errs.InternalError{}
 	}
 
-	// Находим доступных ревьюверов из той же команды (исключая автора)
-	rows, err := tx.Query(reqCtx,
-		`SELECT user_id, id FROM users 
-         WHERE team_name = $1 AND is_active = true AND user_id != $2 
-         ORDER BY id LIMIT 2`,
-		pr.TeamName, pr.AuthorID)
+	// Сериализуем назначения внутри команды, чтобы параллельные PR не выбрали одних и тех же ревьюверов
+	if err := lockTeam(reqCtx, tx, pr.TeamName); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	if err := ensureTeamActive(reqCtx, tx, pr.TeamName); err != nil {
+		return nil, prLoadError(err)
+	}
+
+	settings, err := loadTeamSettings(reqCtx, tx, pr.TeamName)
 	if err != nil {
-		logrus.Error(logPrefix, "Failed to find reviewers: "+err.Error())
+		logrus.Error(logPrefix, err.Error())
 		return nil, &errs.InternalError{}
 	}
-	defer rows.Close()
 
-	var reviewerUserIDs []string
-	var reviewerInternalIDs []int
-	for rows.Next() {
-		var userID string
-		var internalID int
-		if err := rows.Scan(&userID, &internalID); err != nil {
-			logrus.Error(logPrefix, err.Error())
+	var reviewers []reviewer
+	needMoreReviewers := false
+
+	// Черновик создаётся без ревьюверов, они назначаются в /pullRequest/ready
+	if pr.Status != domain.DRAFT {
+		// Выбираем до max_reviewers ревьюверов из той же команды (исключая автора): обязательных и по стратегии команды
+		candidates, err := pickInitialReviewers(reqCtx, tx, settings, authorInternalID, selectors)
+		if err != nil {
+			logrus.Error(logPrefix, "Failed to find reviewers: "+err.Error())
 			return nil, &errs.InternalError{}
 		}
-		reviewerUserIDs = append(reviewerUserIDs, userID)
-		reviewerInternalIDs = append(reviewerInternalIDs, internalID)
-	}
 
-	// Определяем статус need_more_reviewers
-	needMoreReviewers := len(reviewerInternalIDs) < 2
+		reviewers = candidates
+
+		// Определяем статус need_more_reviewers
+		needMoreReviewers = len(reviewers) < settings.MinReviewers
+		pr.Status = domain.OPEN
+	}
 
 	// Устанавливаем поля PR до вставки
 	now := time.Now()
-	pr.Status = domain.OPEN
 	pr.NeedMoreReviewers = needMoreReviewers
 	pr.CreatedAt = now
 	pr.UpdatedAt = now
 
 	// Вставляем PR с правильным author_id (internal ID)
 	if _, err := tx.Exec(reqCtx,
-		`INSERT INTO prs (id, name, author_id, status, need_more_reviewers, created_at, updated_at) 
-         VALUES ($1, $2, $3, $4, $5, $6, $7)`,
-		pr.PrID, pr.PrName, authorInternalID, pr.Status, pr.NeedMoreReviewers, pr.CreatedAt, pr.UpdatedAt); err != nil {
+		`INSERT INTO prs (id, name, author_id, team_name, status, need_more_reviewers, created_at, updated_at) 
+         VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		pr.PrID, pr.PrName, authorInternalID, pr.TeamName, pr.Status, pr.NeedMoreReviewers, pr.CreatedAt, pr.UpdatedAt); err != nil {
 		if strings.Contains(err.Error(), "dublicate") || strings.Contains(err.Error(), "duplicate") {
 			return nil, &errs.AlreadyExistsError{
 				Domain: "pr '" + pr.PrID + "'",
@@ -103,14 +108,19 @@ func (r *PullRequestRepository) CreateNewPR(pr *domain.PullRequest) (assigned_re
 		return nil, &errs.InternalError{}
 	}
 
+	if err := recordEvent(reqCtx, tx, domain.PREvent{PrID: pr.PrID, Type: domain.EventCreated, ActorID: pr.AuthorID}); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+
 	// Назначаем найденных ревьюверов
-	for _, reviewerInternalID := range reviewerInternalIDs {
-		if _, err := tx.Exec(reqCtx,
-			`INSERT INTO pr_reviewers (pr_id, user_id, team_name) VALUES ($1, $2, $3)`,
-			pr.PrID, reviewerInternalID, pr.TeamName); err != nil {
-			logrus.Error(logPrefix, err.Error())
-			return nil, &errs.InternalError{}
-		}
+	if err := insertReviewers(reqCtx, tx, pr.PrID, pr.TeamName, reviewers, pr.AuthorID); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	if states, err = reviewerStates(reqCtx, tx, pr.PrID); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
 	}
 
 	if err := tx.Commit(reqCtx); err != nil {
@@ -118,11 +128,11 @@ func (r *PullRequestRepository) CreateNewPR(pr *domain.PullRequest) (assigned_re
 		return nil, &errs.InternalError{}
 	}
 
-	return reviewerUserIDs, nil
+	return states, nil
 }
 
 // Merge implements domain.PRRepository.
-func (r *PullRequestRepository) Merge(prID string) (pr *domain.PullRequest, assigned_reviewers []string, err error) {
+func (r *PullRequestRepository) Merge(prID string, actor domain.Actor) (pr *domain.PullRequest, states []domain.ReviewerState, err error) {
 	reqCtx, cancel := context.WithTimeout(r.ctx, r.rtimeout)
 	defer cancel()
 
@@ -140,17 +150,19 @@ func (r *PullRequestRepository) Merge(prID string) (pr *domain.PullRequest, assi
 		authorInternalID  int
 		needMoreReviewers bool
 		updatedAt         time.Time
+		mergedAt          *time.Time
 	)
 
 	pr = &domain.PullRequest{}
 
 	err = tx.QueryRow(reqCtx, `
-        SELECT p.status, p.name, p.author_id, p.need_more_reviewers, p.updated_at,
-               u.user_id, u.team_name
+        SELECT p.status, p.name, p.author_id, p.need_more_reviewers, p.updated_at, p.merged_at,
+               u.user_id, p.team_name
         FROM prs p
-        JOIN users u ON p.author_id = u.id
+        JOIN team_members u ON p.author_id = u.id
         WHERE p.id = $1
-    `, prID).Scan(&status, &prName, &authorInternalID, &needMoreReviewers, &updatedAt,
+        FOR UPDATE OF p
+    `, prID).Scan(&status, &prName, &authorInternalID, &needMoreReviewers, &updatedAt, &mergedAt,
 		&pr.AuthorID, &pr.TeamName)
 
 	if err != nil {
@@ -161,6 +173,10 @@ func (r *PullRequestRepository) Merge(prID string) (pr *domain.PullRequest, assi
 		return nil, nil, &errs.InternalError{}
 	}
 
+	if err := authorize(reqCtx, tx, pr.TeamName, actor, domain.TeamRole.CanMerge); err != nil {
+		return nil, nil, prLoadError(err)
+	}
+
 	// Инициализируем объект PR
 	pr = &domain.PullRequest{
 		PrID:              prID,
@@ -169,38 +185,26 @@ func (r *PullRequestRepository) Merge(prID string) (pr *domain.PullRequest, assi
 		TeamName:          pr.TeamName,
 		NeedMoreReviewers: needMoreReviewers,
 		UpdatedAt:         updatedAt,
+		MergedAt:          mergedAt,
 	}
 
 	// Преобразуем статус
 	if status == "MERGED" {
 		pr.Status = domain.MERGED
 		// Если уже мержжен, просто возвращаем информацию без изменений
+	} else if status == "CLOSED" {
+		return nil, nil, &errs.DomainError{Code: codes.PR_CLOSED, Desc: "cannot merge closed PR"}
+	} else if status == "DRAFT" {
+		return nil, nil, &errs.DomainError{Code: codes.PR_DRAFT}
 	} else {
 		pr.Status = domain.OPEN // временно, обновим ниже
 	}
 
-	// Получаем список назначенных ревьюверов
-	rows, err := tx.Query(reqCtx, `
-        SELECT u.user_id 
-        FROM pr_reviewers prr
-        JOIN users u ON prr.user_id = u.id
-        WHERE prr.pr_id = $1
-    `, prID)
-
-	if err != nil {
+	// Получаем назначенных ревьюверов с их решениями
+	if states, err = reviewerStates(reqCtx, tx, prID); err != nil {
 		logrus.Error(logPrefix, err.Error())
 		return nil, nil, &errs.InternalError{}
 	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var reviewerUserID string
-		if err := rows.Scan(&reviewerUserID); err != nil {
-			logrus.Error(logPrefix, err.Error())
-			return nil, nil, &errs.InternalError{}
-		}
-		assigned_reviewers = append(assigned_reviewers, reviewerUserID)
-	}
 
 	// Если PR уже мержжен, просто возвращаем данные
 	if status == "MERGED" {
@@ -208,14 +212,28 @@ func (r *PullRequestRepository) Merge(prID string) (pr *domain.PullRequest, assi
 			logrus.Error(logPrefix, err.Error())
 			return nil, nil, &errs.InternalError{}
 		}
-		return pr, assigned_reviewers, nil
+		return pr, states, nil
+	}
+	// Архив доступен только на чтение; повторный merge выше остаётся идемпотентным
+	if err := ensureTeamActive(reqCtx, tx, pr.TeamName); err != nil {
+		return nil, nil, prLoadError(err)
+	}
+
+	// Проверяем merge-политику команды
+	settings, err := loadTeamSettings(reqCtx, tx, pr.TeamName)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, nil, &errs.InternalError{}
+	}
+	if violations := settings.MergePolicy.Violations(pr, states); len(violations) > 0 {
+		return nil, nil, &errs.DomainError{Code: codes.MERGE_BLOCKED, Details: violations}
 	}
 
 	// Обновляем статус на MERGED
 	now := time.Now()
 	if _, err := tx.Exec(reqCtx, `
         UPDATE prs 
-        SET status = 'MERGED', updated_at = $1 
+        SET status = 'MERGED', updated_at = $1, merged_at = $1
         WHERE id = $2
     `, now, prID); err != nil {
 		logrus.Error(logPrefix, err.Error())
@@ -225,17 +243,23 @@ func (r *PullRequestRepository) Merge(prID string) (pr *domain.PullRequest, assi
 	// Обновляем объект PR
 	pr.Status = domain.MERGED
 	pr.UpdatedAt = now
+	pr.MergedAt = &now
+
+	if err := recordEvent(reqCtx, tx, domain.PREvent{PrID: prID, Type: domain.EventMerged, ActorID: actor.UserID}); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, nil, &errs.InternalError{}
+	}
 
 	if err := tx.Commit(reqCtx); err != nil {
 		logrus.Error(logPrefix, err.Error())
 		return nil, nil, &errs.InternalError{}
 	}
 
-	return pr, assigned_reviewers, nil
+	return pr, states, nil
 }
 
 // Reassign implements domain.PRRepository.
-func (r *PullRequestRepository) Reassign(prID string, userID string) (pr *domain.PullRequest, assigned_reviewers []string, replacedUserID string, err error) {
+func (r *PullRequestRepository) Reassign(prID string, userID string, newUserID string, reason string, actor domain.Actor, selectors *domain.ReviewerSelectors) (pr *domain.PullRequest, states []domain.ReviewerState, replacedUserID string, err error) {
 	reqCtx, cancel := context.WithTimeout(r.ctx, r.rtimeout)
 	defer cancel()
 
@@ -246,243 +270,575 @@ func (r *PullRequestRepository) Reassign(prID string, userID string) (pr *domain
 	}
 	defer tx.Rollback(reqCtx)
 
-	// Проверяем статус PR
-	var status string
-	err = tx.QueryRow(reqCtx, `SELECT status FROM prs WHERE id = $1`, prID).Scan(&status)
+	// Блокируем PR, чтобы параллельные merge и close не сменили статус посреди замены ревьювера
+	pr, authorInternalID, err := lockPR(reqCtx, tx, prID)
+	if err != nil {
+		return nil, nil, "", prLoadError(err)
+	}
+	switch pr.Status {
+	case domain.MERGED:
+		return nil, nil, "", &errs.DomainError{Code: codes.PR_MERGED}
+	case domain.CLOSED:
+		return nil, nil, "", &errs.DomainError{Code: codes.PR_CLOSED}
+	case domain.DRAFT:
+		return nil, nil, "", &errs.DomainError{Code: codes.PR_DRAFT, Desc: "cannot reassign on draft PR"}
+	}
+	teamName := pr.TeamName
+
+	// Проверяем, что пользователь назначен ревьювером на этот PR
+	var reviewerInternalID int
+	err = tx.QueryRow(reqCtx, `
+        SELECT u.id FROM team_members u
+        JOIN pr_reviewers prr ON u.id = prr.user_id
+        WHERE u.user_id = $1 AND prr.pr_id = $2
+    `, userID, prID).Scan(&reviewerInternalID)
+
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, nil, "", &errs.NotFoundError{Domain: "pull request"}
+			return nil, nil, "", &errs.DomainError{Code: codes.NOT_ASSIGNED}
 		}
 		logrus.Error(logPrefix, err.Error())
 		return nil, nil, "", &errs.InternalError{}
 	}
 
-	if status == "MERGED" {
-		return nil, nil, "", &errs.DomainError{Code: codes.PR_MERGED}
+	// Ищем кандидата для замены стратегией команды (активный пользователь из той же команды,
+	// кроме автора и уже назначенных ревьюверов)
+	if err := lockTeam(reqCtx, tx, teamName); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, nil, "", &errs.InternalError{}
+	}
+	settings, err := loadTeamSettings(reqCtx, tx, teamName)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, nil, "", &errs.InternalError{}
+	}
+	var candidate *reviewer
+	if newUserID != "" {
+		// Явно выбранная замена проходит те же проверки, что и кандидаты стратегии
+		if candidate, err = pickTarget(reqCtx, tx, teamName, prID, authorInternalID, newUserID); err != nil {
+			return nil, nil, "", prLoadError(err)
+		}
+	} else {
+		if candidate, err = pickReplacement(reqCtx, tx, settings, prID, authorInternalID, selectors); err != nil {
+			logrus.Error(logPrefix, err.Error())
+			return nil, nil, "", &errs.InternalError{}
+		}
+		if candidate == nil {
+			return nil, nil, "", &errs.DomainError{Code: codes.NO_CANDIDATE}
+		}
+	}
+
+	// Меняем ревьювера и пересчитываем need_more_reviewers
+	old := reviewer{id: reviewerInternalID, userID: userID}
+	if pr.NeedMoreReviewers, err = swapReviewer(reqCtx, tx, settings, prID, old, candidate, actor.UserID, reason); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, nil, "", &errs.InternalError{}
+	}
+
+	// Получаем обновленный список ревьюверов
+	if states, err = reviewerStates(reqCtx, tx, prID); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, nil, "", &errs.InternalError{}
+	}
+
+	pr.UpdatedAt = time.Now()
+
+	if err := tx.Commit(reqCtx); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, nil, "", &errs.InternalError{}
+	}
+
+	return pr, states, candidate.userID, nil
+}
+
+// GetWithUser implements domain.PRRepository.
+func (r *PullRequestRepository) GetWithUser(user *domain.User, filter domain.UserPRsFilter) ([]domain.UserPR, error) {
+	reqCtx, cancel := context.WithTimeout(r.ctx, r.rtimeout)
+	defer cancel()
+
+	tx, err := r.pool.Begin(reqCtx)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	defer tx.Rollback(reqCtx)
+
+	// Без team_name учитываются все членства пользователя, включая прошлые
+	var internalUserIDs []int
+	if err := tx.QueryRow(reqCtx,
+		`SELECT COALESCE(array_agg(id), '{}') FROM team_members WHERE user_id = $1 AND ($2 = '' OR team_name = $2)`,
+		user.UserID, user.TeamName).Scan(&internalUserIDs); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	if len(internalUserIDs) == 0 {
+		return nil, &errs.NotFoundError{Domain: "user"}
 	}
 
-	// Получаем информацию о PR и авторе
 	var (
-		prName            string
-		authorInternalID  int
-		needMoreReviewers bool
-		createdAt         time.Time
-		updatedAt         time.Time
-		authorUserID      string
-		teamName          string
+		afterTime *time.Time
+		afterID   string
 	)
+	if filter.After != nil {
+		afterTime, afterID = &filter.After.Time, filter.After.PrID
+	}
 
-	err = tx.QueryRow(reqCtx, `
-        SELECT p.name, p.author_id, p.need_more_reviewers, p.created_at, p.updated_at,
-               u.user_id, u.team_name
+	rows, err := tx.Query(reqCtx, `
+        SELECT 
+            p.id, p.name, 
+            author.user_id as author_user_id, 
+            p.team_name,
+            p.status, p.need_more_reviewers, p.created_at, p.updated_at,
+            CASE WHEN p.author_id = ANY($1) THEN 'author' ELSE 'reviewer' END as role
         FROM prs p
-        JOIN users u ON p.author_id = u.id
-        WHERE p.id = $1
-    `, prID).Scan(&prName, &authorInternalID, &needMoreReviewers, &createdAt, &updatedAt,
-		&authorUserID, &teamName)
+        JOIN team_members author ON p.author_id = author.id
+        WHERE (
+               ($2 IN ('any', 'author') AND p.author_id = ANY($1))
+            OR ($2 IN ('any', 'reviewer') AND p.id IN (
+                   SELECT pr_id FROM pr_reviewers WHERE user_id = ANY($1)
+               ))
+          )
+          AND ($3 = '' OR p.status::text = $3)
+          AND ($4::timestamptz IS NULL OR (p.created_at, p.id) < ($4, $5))
+        ORDER BY p.created_at DESC, p.id DESC
+        LIMIT $6
+    `, internalUserIDs, string(filter.Role), string(filter.Status), afterTime, afterID, filter.Limit)
 
 	if err != nil {
 		logrus.Error(logPrefix, err.Error())
-		return nil, nil, "", &errs.InternalError{}
+		return nil, &errs.InternalError{}
 	}
+	defer rows.Close()
 
-	// Проверяем, что пользователь назначен ревьювером на этот PR
-	var reviewerInternalID int
-	err = tx.QueryRow(reqCtx, `
-        SELECT u.id FROM users u
-        JOIN pr_reviewers prr ON u.id = prr.user_id
-        WHERE u.user_id = $1 AND prr.pr_id = $2
-    `, userID, prID).Scan(&reviewerInternalID)
+	var pullRequests []domain.UserPR
+	for rows.Next() {
+		var pr domain.UserPR
+		var status, role string
 
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, nil, "", &errs.DomainError{Code: codes.NOT_ASSIGNED}
+		err := rows.Scan(
+			&pr.PrID, &pr.PrName,
+			&pr.AuthorID, &pr.TeamName,
+			&status, &pr.NeedMoreReviewers, &pr.CreatedAt, &pr.UpdatedAt,
+			&role,
+		)
+		if err != nil {
+			logrus.Error(logPrefix, err.Error())
+			return nil, &errs.InternalError{}
 		}
+
+		// Конвертируем строковые статус и роль в доменные типы
+		pr.Status = domain.STATUS(status)
+		pr.Role = domain.PRRole(role)
+
+		pullRequests = append(pullRequests, pr)
+	}
+
+	if err := rows.Err(); err != nil {
 		logrus.Error(logPrefix, err.Error())
-		return nil, nil, "", &errs.InternalError{}
+		return nil, &errs.InternalError{}
 	}
+	rows.Close()
 
-	// Ищем кандидата для замены (активный пользователь из той же команды, кроме автора и текущего ревьювера)
-	var candidateUserID string
-	var candidateInternalID int
-	err = tx.QueryRow(reqCtx, `
-        SELECT user_id, id FROM users 
-        WHERE team_name = $1 
-          AND is_active = true 
-          AND user_id != $2 
-          AND id != $3
-          AND id NOT IN (
-              SELECT user_id FROM pr_reviewers WHERE pr_id = $4
-          )
-        ORDER BY id 
-        LIMIT 1
-    `, teamName, authorUserID, reviewerInternalID, prID).Scan(&candidateUserID, &candidateInternalID)
+	prIDs := make([]string, len(pullRequests))
+	for index, pr := range pullRequests {
+		prIDs[index] = pr.PrID
+	}
+	states, err := reviewerStatesByPR(reqCtx, tx, prIDs)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	for index := range pullRequests {
+		pullRequests[index].Reviewers = states[pullRequests[index].PrID]
+	}
+
+	if err := tx.Commit(reqCtx); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+
+	return pullRequests, nil
+}
+
+// SubmitReview implements domain.PRRepository.
+func (r *PullRequestRepository) SubmitReview(prID string, reviewerID string, state domain.ReviewState, body string) (pr *domain.PullRequest, reviewers []domain.ReviewerState, err error) {
+	reqCtx, cancel := context.WithTimeout(r.ctx, r.rtimeout)
+	defer cancel()
 
+	tx, err := r.pool.Begin(reqCtx)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, nil, &errs.InternalError{}
+	}
+	defer tx.Rollback(reqCtx)
+
+	pr, _, err = lockPR(reqCtx, tx, prID)
+	if err != nil {
+		return nil, nil, prLoadError(err)
+	}
+	if pr.Status == domain.MERGED {
+		return nil, nil, &errs.DomainError{Code: codes.PR_MERGED, Desc: "cannot review merged PR"}
+	}
+	if pr.Status == domain.CLOSED {
+		return nil, nil, &errs.DomainError{Code: codes.PR_CLOSED, Desc: "cannot review closed PR"}
+	}
+	if pr.Status == domain.DRAFT {
+		return nil, nil, &errs.DomainError{Code: codes.PR_DRAFT, Desc: "cannot review draft PR"}
+	}
+
+	// Решение может оставить только назначенный ревьювер
+	var reviewerInternalID int
+	err = tx.QueryRow(reqCtx, `
+        SELECT u.id FROM team_members u
+        JOIN pr_reviewers prr ON u.id = prr.user_id
+        WHERE u.user_id = $1 AND prr.pr_id = $2
+    `, reviewerID, prID).Scan(&reviewerInternalID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, nil, "", &errs.DomainError{Code: codes.NO_CANDIDATE}
+			return nil, nil, &errs.DomainError{Code: codes.NOT_ASSIGNED}
 		}
 		logrus.Error(logPrefix, err.Error())
-		return nil, nil, "", &errs.InternalError{}
+		return nil, nil, &errs.InternalError{}
 	}
 
-	// Удаляем старого ревьювера
+	var reviewBody *string
+	if body != "" {
+		reviewBody = &body
+	}
 	if _, err := tx.Exec(reqCtx, `
-        DELETE FROM pr_reviewers 
-        WHERE pr_id = $1 AND user_id = $2
-    `, prID, reviewerInternalID); err != nil {
+        INSERT INTO pr_reviews (pr_id, user_id, state, body) VALUES ($1, $2, $3, $4)
+    `, prID, reviewerInternalID, state, reviewBody); err != nil {
 		logrus.Error(logPrefix, err.Error())
-		return nil, nil, "", &errs.InternalError{}
+		return nil, nil, &errs.InternalError{}
+	}
+	if err := recordEvent(reqCtx, tx, domain.PREvent{
+		PrID: prID, Type: domain.EventReviewSubmitted, ActorID: reviewerID, ReviewerID: reviewerID, Details: string(state),
+	}); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, nil, &errs.InternalError{}
 	}
 
-	// Добавляем нового ревьювера
-	if _, err := tx.Exec(reqCtx, `
-        INSERT INTO pr_reviewers (pr_id, user_id, team_name) 
-        VALUES ($1, $2, $3)
-    `, prID, candidateInternalID, teamName); err != nil {
+	if reviewers, err = reviewerStates(reqCtx, tx, prID); err != nil {
 		logrus.Error(logPrefix, err.Error())
-		return nil, nil, "", &errs.InternalError{}
+		return nil, nil, &errs.InternalError{}
 	}
 
-	// Проверяем количество ревьюверов для определения need_more_reviewers
-	var reviewerCount int
-	err = tx.QueryRow(reqCtx, `
-        SELECT COUNT(*) FROM pr_reviewers WHERE pr_id = $1
-    `, prID).Scan(&reviewerCount)
+	if err := tx.Commit(reqCtx); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, nil, &errs.InternalError{}
+	}
 
+	return pr, reviewers, nil
+}
+
+// Close implements domain.PRRepository.
+func (r *PullRequestRepository) Close(prID string, actor domain.Actor) (pr *domain.PullRequest, states []domain.ReviewerState, err error) {
+	reqCtx, cancel := context.WithTimeout(r.ctx, r.rtimeout)
+	defer cancel()
+
+	tx, err := r.pool.Begin(reqCtx)
 	if err != nil {
 		logrus.Error(logPrefix, err.Error())
-		return nil, nil, "", &errs.InternalError{}
+		return nil, nil, &errs.InternalError{}
 	}
+	defer tx.Rollback(reqCtx)
 
-	newNeedMoreReviewers := reviewerCount < 2
+	pr, _, err = lockPR(reqCtx, tx, prID)
+	if err != nil {
+		return nil, nil, prLoadError(err)
+	}
 
-	// Обновляем флаг need_more_reviewers если изменился
-	if needMoreReviewers != newNeedMoreReviewers {
-		if _, err := tx.Exec(reqCtx, `
-            UPDATE prs SET need_more_reviewers = $1, updated_at = $2 
-            WHERE id = $3
-        `, newNeedMoreReviewers, time.Now(), prID); err != nil {
+	switch pr.Status {
+	case domain.MERGED:
+		return nil, nil, &errs.DomainError{Code: codes.PR_MERGED, Desc: "cannot close merged PR"}
+	case domain.OPEN, domain.DRAFT:
+		// Ревьюверы остаются в истории PR, но CLOSED PR больше не входит в их OPEN нагрузку.
+		// Прежний статус запоминается, чтобы reopen вернул черновик черновиком
+		pr.Status = domain.CLOSED
+		pr.UpdatedAt = time.Now()
+		if _, err := tx.Exec(reqCtx,
+			`UPDATE prs SET status = 'CLOSED', closed_from = status, updated_at = $1 WHERE id = $2`, pr.UpdatedAt, prID); err != nil {
 			logrus.Error(logPrefix, err.Error())
-			return nil, nil, "", &errs.InternalError{}
+			return nil, nil, &errs.InternalError{}
+		}
+		if err := recordEvent(reqCtx, tx, domain.PREvent{PrID: prID, Type: domain.EventClosed, ActorID: actor.UserID}); err != nil {
+			logrus.Error(logPrefix, err.Error())
+			return nil, nil, &errs.InternalError{}
 		}
-		needMoreReviewers = newNeedMoreReviewers
 	}
 
-	// Получаем обновленный список ревьюверов
-	rows, err := tx.Query(reqCtx, `
-        SELECT u.user_id 
-        FROM pr_reviewers prr
-        JOIN users u ON prr.user_id = u.id
-        WHERE prr.pr_id = $1
-    `, prID)
+	if states, err = reviewerStates(reqCtx, tx, prID); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, nil, &errs.InternalError{}
+	}
+
+	if err := tx.Commit(reqCtx); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, nil, &errs.InternalError{}
+	}
 
+	return pr, states, nil
+}
+
+// Reopen implements domain.PRRepository.
+func (r *PullRequestRepository) Reopen(prID string, actor domain.Actor, selectors *domain.ReviewerSelectors) (pr *domain.PullRequest, states []domain.ReviewerState, reassignments []domain.Reassignment, err error) {
+	reqCtx, cancel := context.WithTimeout(r.ctx, r.rtimeout)
+	defer cancel()
+
+	tx, err := r.pool.Begin(reqCtx)
 	if err != nil {
 		logrus.Error(logPrefix, err.Error())
-		return nil, nil, "", &errs.InternalError{}
+		return nil, nil, nil, &errs.InternalError{}
 	}
-	defer rows.Close()
+	defer tx.Rollback(reqCtx)
 
-	for rows.Next() {
-		var reviewerUserID string
-		if err := rows.Scan(&reviewerUserID); err != nil {
+	pr, authorInternalID, err := lockPR(reqCtx, tx, prID)
+	if err != nil {
+		return nil, nil, nil, prLoadError(err)
+	}
+
+	switch pr.Status {
+	case domain.MERGED:
+		return nil, nil, nil, &errs.DomainError{Code: codes.PR_MERGED, Desc: "cannot reopen merged PR"}
+	case domain.CLOSED:
+		// Закрытый черновик возвращается черновиком: ревьюверов ему назначит /pullRequest/ready
+		var status string
+		if err := tx.QueryRow(reqCtx, `
+            UPDATE prs SET status = COALESCE(closed_from, 'OPEN'), closed_from = NULL
+            WHERE id = $1
+            RETURNING status
+        `, prID).Scan(&status); err != nil {
 			logrus.Error(logPrefix, err.Error())
-			return nil, nil, "", &errs.InternalError{}
+			return nil, nil, nil, &errs.InternalError{}
+		}
+		pr.Status = domain.STATUS(status)
+		if err := recordEvent(reqCtx, tx, domain.PREvent{PrID: prID, Type: domain.EventReopened, ActorID: actor.UserID}); err != nil {
+			logrus.Error(logPrefix, err.Error())
+			return nil, nil, nil, &errs.InternalError{}
+		}
+		if pr.Status == domain.DRAFT {
+			pr.UpdatedAt = time.Now()
+			break
+		}
+
+		if err := lockTeam(reqCtx, tx, pr.TeamName); err != nil {
+			logrus.Error(logPrefix, err.Error())
+			return nil, nil, nil, &errs.InternalError{}
+		}
+		settings, err := loadTeamSettings(reqCtx, tx, pr.TeamName)
+		if err != nil {
+			logrus.Error(logPrefix, err.Error())
+			return nil, nil, nil, &errs.InternalError{}
+		}
+
+		// Ревьюверов, ставших неактивными или покинувших команду, пока PR был закрыт, меняем по правилам reassign
+		rows, err := tx.Query(reqCtx, `
+            SELECT u.id, u.user_id
+            FROM pr_reviewers prr
+            JOIN team_members u ON u.id = prr.user_id
+            WHERE prr.pr_id = $1
+              AND (u.is_active = false OR u.team_name IS DISTINCT FROM prr.team_name)
+            ORDER BY prr.assigned_at, u.id
+        `, prID)
+		if err != nil {
+			logrus.Error(logPrefix, err.Error())
+			return nil, nil, nil, &errs.InternalError{}
+		}
+		var inactive []reviewer
+		for rows.Next() {
+			var rev reviewer
+			if err := rows.Scan(&rev.id, &rev.userID); err != nil {
+				rows.Close()
+				logrus.Error(logPrefix, err.Error())
+				return nil, nil, nil, &errs.InternalError{}
+			}
+			inactive = append(inactive, rev)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			logrus.Error(logPrefix, err.Error())
+			return nil, nil, nil, &errs.InternalError{}
+		}
+
+		for _, old := range inactive {
+			candidate, err := pickReplacement(reqCtx, tx, settings, prID, authorInternalID, selectors)
+			if err != nil {
+				logrus.Error(logPrefix, err.Error())
+				return nil, nil, nil, &errs.InternalError{}
+			}
+			if pr.NeedMoreReviewers, err = swapReviewer(reqCtx, tx, settings, prID, old, candidate, actor.UserID, ""); err != nil {
+				logrus.Error(logPrefix, err.Error())
+				return nil, nil, nil, &errs.InternalError{}
+			}
+			reassignment := domain.Reassignment{PrID: prID, OldReviewerID: old.userID}
+			if candidate != nil {
+				reassignment.NewReviewerID = candidate.userID
+			}
+			reassignments = append(reassignments, reassignment)
+		}
+
+		// Настройки команды могли измениться, пока PR был закрыт
+		if pr.NeedMoreReviewers, err = refreshNeedMoreReviewers(reqCtx, tx, settings, prID); err != nil {
+			logrus.Error(logPrefix, err.Error())
+			return nil, nil, nil, &errs.InternalError{}
+		}
+		pr.UpdatedAt = time.Now()
+		for index := range reassignments {
+			reassignments[index].NeedMoreReviewers = pr.NeedMoreReviewers
 		}
-		assigned_reviewers = append(assigned_reviewers, reviewerUserID)
 	}
 
-	// Создаем объект PR для возврата
-	pr = &domain.PullRequest{
-		PrID:              prID,
-		PrName:            prName,
-		AuthorID:          authorUserID,
-		TeamName:          teamName,
-		Status:            domain.OPEN, // Мы знаем, что статус не MERGED
-		NeedMoreReviewers: needMoreReviewers,
-		CreatedAt:         createdAt,
-		UpdatedAt:         time.Now(), // Обновляем время
+	if states, err = reviewerStates(reqCtx, tx, prID); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, nil, nil, &errs.InternalError{}
 	}
 
 	if err := tx.Commit(reqCtx); err != nil {
 		logrus.Error(logPrefix, err.Error())
-		return nil, nil, "", &errs.InternalError{}
+		return nil, nil, nil, &errs.InternalError{}
 	}
 
-	return pr, assigned_reviewers, candidateUserID, nil
+	return pr, states, reassignments, nil
 }
 
-// GetWithUser implements domain.PRRepository.
-func (r *PullRequestRepository) GetWithUser(user *domain.User) (*[]domain.PullRequest, error) {
+// Get implements domain.PRRepository.
+func (r *PullRequestRepository) Get(prID string) (pr *domain.PullRequest, reviewers []domain.ReviewerState, err error) {
 	reqCtx, cancel := context.WithTimeout(r.ctx, r.rtimeout)
 	defer cancel()
 
 	tx, err := r.pool.Begin(reqCtx)
 	if err != nil {
 		logrus.Error(logPrefix, err.Error())
-		return nil, &errs.InternalError{}
+		return nil, nil, &errs.InternalError{}
 	}
 	defer tx.Rollback(reqCtx)
 
-	var internalUserID int
-	err = tx.QueryRow(reqCtx,
-		`SELECT id FROM users WHERE user_id = $1 AND team_name = $2`,
-		user.UserID, user.TeamName).Scan(&internalUserID)
+	pr, _, err = selectPR(reqCtx, tx, prID, "")
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, &errs.NotFoundError{Domain: "user"}
+			return nil, nil, &errs.NotFoundError{Domain: "pull request"}
 		}
 		logrus.Error(logPrefix, err.Error())
-		return nil, &errs.InternalError{}
+		return nil, nil, &errs.InternalError{}
 	}
 
-	rows, err := tx.Query(reqCtx, `
-        SELECT 
-            p.id, p.name, 
-            author.user_id as author_user_id, 
-            author.team_name as author_team_name,
-            p.status, p.need_more_reviewers, p.created_at, p.updated_at
-        FROM prs p
-        JOIN users author ON p.author_id = author.id
-        WHERE p.author_id = $1 
-           OR p.id IN (
-               SELECT pr_id FROM pr_reviewers WHERE user_id = $1
-           )
-        ORDER BY p.created_at DESC
-    `, internalUserID)
+	if reviewers, err = reviewerStates(reqCtx, tx, prID); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, nil, &errs.InternalError{}
+	}
 
+	if err := tx.Commit(reqCtx); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, nil, &errs.InternalError{}
+	}
+
+	return pr, reviewers, nil
+}
+
+// prSortColumns - колонки и направление keyset-пагинации для каждого порядка сортировки
+var prSortColumns = map[domain.PRSort]struct{ column, dir, cmp string }{
+	domain.SortCreatedDesc: {"p.created_at", "DESC", "<"},
+	domain.SortCreatedAsc:  {"p.created_at", "ASC", ">"},
+	domain.SortUpdatedDesc: {"p.updated_at", "DESC", "<"},
+	domain.SortUpdatedAsc:  {"p.updated_at", "ASC", ">"},
+}
+
+// List implements domain.PRRepository.
+func (r *PullRequestRepository) List(filter domain.PRListFilter) ([]domain.PRListItem, error) {
+	reqCtx, cancel := context.WithTimeout(r.ctx, r.rtimeout)
+	defer cancel()
+
+	sort, ok := prSortColumns[filter.Sort]
+	if !ok {
+		sort = prSortColumns[domain.SortCreatedDesc]
+	}
+	var (
+		afterTime *time.Time
+		afterID   string
+	)
+	if filter.After != nil {
+		afterTime, afterID = &filter.After.Time, filter.After.PrID
+	}
+
+	rows, err := r.pool.Query(reqCtx, `
+        SELECT p.id, p.name, author.user_id, p.team_name,
+               p.status, p.need_more_reviewers, p.created_at, p.updated_at, p.merged_at,
+               ARRAY(
+                   SELECT ru.user_id
+                   FROM pr_reviewers prr
+                   JOIN team_members ru ON ru.id = prr.user_id
+                   WHERE prr.pr_id = p.id
+                   ORDER BY prr.assigned_at, ru.user_id
+               )
+        FROM prs p
+        JOIN team_members author ON p.author_id = author.id
+        WHERE ($1 = '' OR p.team_name = $1)
+          AND ($2 = '' OR p.status::text = $2)
+          AND ($3 = '' OR author.user_id = $3)
+          AND ($4 = '' OR EXISTS (
+                  SELECT 1 FROM pr_reviewers prr
+                  JOIN team_members ru ON ru.id = prr.user_id
+                  WHERE prr.pr_id = p.id AND ru.user_id = $4))
+          AND ($5::boolean IS NULL OR p.need_more_reviewers = $5)
+          AND ($6::timestamptz IS NULL OR p.created_at >= $6)
+          AND ($7::timestamptz IS NULL OR p.created_at < $7)
+          AND ($8::timestamptz IS NULL OR (`+sort.column+`, p.id) `+sort.cmp+` ($8, $9))
+        ORDER BY `+sort.column+` `+sort.dir+`, p.id `+sort.dir+`
+        LIMIT $10
+    `, filter.TeamName, string(filter.Status), filter.AuthorID, filter.ReviewerID, filter.NeedMoreReviewers,
+		filter.CreatedFrom, filter.CreatedTo, afterTime, afterID, filter.Limit)
 	if err != nil {
 		logrus.Error(logPrefix, err.Error())
 		return nil, &errs.InternalError{}
 	}
 	defer rows.Close()
 
-	var pullRequests []domain.PullRequest
+	items := make([]domain.PRListItem, 0, filter.Limit)
 	for rows.Next() {
-		var pr domain.PullRequest
-		var status string
-
-		err := rows.Scan(
-			&pr.PrID, &pr.PrName,
-			&pr.AuthorID, &pr.TeamName,
-			&status, &pr.NeedMoreReviewers, &pr.CreatedAt, &pr.UpdatedAt,
+		var (
+			item   domain.PRListItem
+			status string
 		)
-		if err != nil {
+		if err := rows.Scan(&item.PrID, &item.PrName, &item.AuthorID, &item.TeamName,
+			&status, &item.NeedMoreReviewers, &item.CreatedAt, &item.UpdatedAt, &item.MergedAt,
+			&item.Reviewers); err != nil {
 			logrus.Error(logPrefix, err.Error())
 			return nil, &errs.InternalError{}
 		}
+		item.Status = domain.STATUS(status)
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
 
-		// Конвертируем строковый статус в доменный тип
-		if status == string(domain.OPEN) {
-			pr.Status = domain.OPEN
-		} else if status == "MERGED" {
-			pr.Status = domain.MERGED
-		}
+	return items, nil
+}
 
-		pullRequests = append(pullRequests, pr)
+// History implements domain.PRRepository.
+func (r *PullRequestRepository) History(prID string) ([]domain.PREvent, error) {
+	reqCtx, cancel := context.WithTimeout(r.ctx, r.rtimeout)
+	defer cancel()
+
+	tx, err := r.pool.Begin(reqCtx)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
 	}
+	defer tx.Rollback(reqCtx)
 
-	if err := rows.Err(); err != nil {
+	var exists bool
+	if err := tx.QueryRow(reqCtx, `SELECT EXISTS (SELECT 1 FROM prs WHERE id = $1)`, prID).Scan(&exists); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	if !exists {
+		return nil, &errs.NotFoundError{Domain: "pull request"}
+	}
+
+	events, err := prHistory(reqCtx, tx, prID)
+	if err != nil {
 		logrus.Error(logPrefix, err.Error())
 		return nil, &errs.InternalError{}
 	}
@@ -491,6 +847,191 @@ func (r *PullRequestRepository) GetWithUser(user *domain.User) (*[]domain.PullRe
 		logrus.Error(logPrefix, err.Error())
 		return nil, &errs.InternalError{}
 	}
+	return events, nil
+}
+
+// Ready implements domain.PRRepository.
+func (r *PullRequestRepository) Ready(prID string, actor domain.Actor, selectors *domain.ReviewerSelectors) (pr *domain.PullRequest, states []domain.ReviewerState, err error) {
+	reqCtx, cancel := context.WithTimeout(r.ctx, r.rtimeout)
+	defer cancel()
+
+	tx, err := r.pool.Begin(reqCtx)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, nil, &errs.InternalError{}
+	}
+	defer tx.Rollback(reqCtx)
+
+	pr, authorInternalID, err := lockPR(reqCtx, tx, prID)
+	if err != nil {
+		return nil, nil, prLoadError(err)
+	}
+
+	switch pr.Status {
+	case domain.MERGED:
+		return nil, nil, &errs.DomainError{Code: codes.PR_MERGED, Desc: "cannot mark merged PR as ready"}
+	case domain.CLOSED:
+		return nil, nil, &errs.DomainError{Code: codes.PR_CLOSED, Desc: "cannot mark closed PR as ready"}
+	case domain.DRAFT:
+		// Назначение ревьюверов как при создании PR
+		if err := lockTeam(reqCtx, tx, pr.TeamName); err != nil {
+			logrus.Error(logPrefix, err.Error())
+			return nil, nil, &errs.InternalError{}
+		}
+		settings, err := loadTeamSettings(reqCtx, tx, pr.TeamName)
+		if err != nil {
+			logrus.Error(logPrefix, err.Error())
+			return nil, nil, &errs.InternalError{}
+		}
+		candidates, err := pickInitialReviewers(reqCtx, tx, settings, authorInternalID, selectors)
+		if err != nil {
+			logrus.Error(logPrefix, "Failed to find reviewers: "+err.Error())
+			return nil, nil, &errs.InternalError{}
+		}
+		if err := recordEvent(reqCtx, tx, domain.PREvent{PrID: prID, Type: domain.EventReady, ActorID: actor.UserID}); err != nil {
+			logrus.Error(logPrefix, err.Error())
+			return nil, nil, &errs.InternalError{}
+		}
+		if err := insertReviewers(reqCtx, tx, prID, pr.TeamName, candidates, actor.UserID); err != nil {
+			logrus.Error(logPrefix, err.Error())
+			return nil, nil, &errs.InternalError{}
+		}
+
+		pr.Status = domain.OPEN
+		pr.NeedMoreReviewers = len(candidates) < settings.MinReviewers
+		pr.UpdatedAt = time.Now()
+		if _, err := tx.Exec(reqCtx, `
+            UPDATE prs SET status = 'OPEN', need_more_reviewers = $1, updated_at = $2
+            WHERE id = $3
+        `, pr.NeedMoreReviewers, pr.UpdatedAt, prID); err != nil {
+			logrus.Error(logPrefix, err.Error())
+			return nil, nil, &errs.InternalError{}
+		}
+	}
+
+	if states, err = reviewerStates(reqCtx, tx, prID); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, nil, &errs.InternalError{}
+	}
+
+	if err := tx.Commit(reqCtx); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, nil, &errs.InternalError{}
+	}
+
+	return pr, states, nil
+}
+
+// insertReviewers назначает ревьюверов на PR и записывает назначения в историю от имени actorID
+func insertReviewers(ctx context.Context, tx pgx.Tx, prID, teamName string, reviewers []reviewer, actorID string) error {
+	for _, r := range reviewers {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO pr_reviewers (pr_id, user_id, team_name) VALUES ($1, $2, $3)`,
+			prID, r.id, teamName); err != nil {
+			return err
+		}
+	}
+	return recordAssignments(ctx, tx, prID, actorID, reviewers)
+}
+
+// lockPR блокирует строку PR до конца транзакции и возвращает PR вместе с внутренним id автора.
+// Если PR нет, возвращает pgx.ErrNoRows; PR архивной команды изменять нельзя.
+func lockPR(ctx context.Context, tx pgx.Tx, prID string) (*domain.PullRequest, int, error) {
+	pr, authorInternalID, err := selectPR(ctx, tx, prID, "FOR UPDATE OF p")
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := ensureTeamActive(ctx, tx, pr.TeamName); err != nil {
+		return nil, 0, err
+	}
+	return pr, authorInternalID, nil
+}
+
+// prLoadError переводит ошибку lockPR в ошибку сервиса
+func prLoadError(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &errs.NotFoundError{Domain: "pull request"}
+	}
+	var domainErr *errs.DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr
+	}
+	var forbiddenErr *errs.ForbiddenError
+	if errors.As(err, &forbiddenErr) {
+		return forbiddenErr
+	}
+	logrus.Error(logPrefix, err.Error())
+	return &errs.InternalError{}
+}
+
+// selectPR читает PR вместе с внутренним id автора; suffix дописывается в конец запроса (например, блокировка)
+func selectPR(ctx context.Context, tx pgx.Tx, prID string, suffix string) (*domain.PullRequest, int, error) {
+	var (
+		pr               = &domain.PullRequest{PrID: prID}
+		authorInternalID int
+		status           string
+	)
+	err := tx.QueryRow(ctx, `
+        SELECT p.name, p.author_id, p.status, p.need_more_reviewers, p.created_at, p.updated_at, p.merged_at,
+               u.user_id, p.team_name
+        FROM prs p
+        JOIN team_members u ON p.author_id = u.id
+        WHERE p.id = $1
+        `+suffix, prID).Scan(&pr.PrName, &authorInternalID, &status, &pr.NeedMoreReviewers, &pr.CreatedAt, &pr.UpdatedAt, &pr.MergedAt,
+		&pr.AuthorID, &pr.TeamName)
+	if err != nil {
+		return nil, 0, err
+	}
+	pr.Status = domain.STATUS(status)
+	return pr, authorInternalID, nil
+}
 
-	return &pullRequests, nil
+// reviewerStates возвращает назначенных ревьюверов PR с их последним решением.
+// Решения, оставленные до текущего назначения (например, до переназначения), не учитываются.
+func reviewerStates(ctx context.Context, tx pgx.Tx, prID string) ([]domain.ReviewerState, error) {
+	states, err := reviewerStatesByPR(ctx, tx, []string{prID})
+	if err != nil {
+		return nil, err
+	}
+	return states[prID], nil
+}
+
+// reviewerStatesByPR - reviewerStates для нескольких PR одним запросом
+func reviewerStatesByPR(ctx context.Context, tx pgx.Tx, prIDs []string) (map[string][]domain.ReviewerState, error) {
+	rows, err := tx.Query(ctx, `
+        SELECT prr.pr_id, u.user_id, prr.assigned_at, last.state, last.created_at
+        FROM pr_reviewers prr
+        JOIN team_members u ON u.id = prr.user_id
+        LEFT JOIN LATERAL (
+            SELECT state, created_at FROM pr_reviews
+            WHERE pr_id = prr.pr_id AND user_id = prr.user_id
+              AND created_at >= prr.assigned_at
+            ORDER BY created_at DESC, id DESC
+            LIMIT 1
+        ) last ON true
+        WHERE prr.pr_id = ANY($1)
+        ORDER BY prr.pr_id, prr.assigned_at, u.user_id
+    `, prIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	states := make(map[string][]domain.ReviewerState, len(prIDs))
+	for rows.Next() {
+		var (
+			prID  string
+			s     domain.ReviewerState
+			state *string
+		)
+		if err := rows.Scan(&prID, &s.UserID, &s.AssignedAt, &state, &s.SubmittedAt); err != nil {
+			return nil, err
+		}
+		s.State = domain.PENDING
+		if state != nil {
+			s.State = domain.ReviewState(*state)
+		}
+		states[prID] = append(states[prID], s)
+	}
+	return states, rows.Err()
 }
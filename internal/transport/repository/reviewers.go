@@ -0,0 +1,498 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"pr-manage-service/internal/domain"
+	"pr-manage-service/pkg/codes"
+	"pr-manage-service/pkg/errs"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// reviewer - кандидат в ревьюверы: внутренний id (serial) и внешний user_id
+type reviewer struct {
+	id     int
+	userID string
+
+	mandatory bool // лид - обязательный ревьювер новых PR команды
+	limited   bool // у пользователя задан max_open_reviews
+	remaining int  // сколько ещё OPEN ревью можно назначить при limited
+}
+
+// openReviewsSQL - подзапрос: число OPEN ревью пользователя userColumn во всех его командах
+func openReviewsSQL(userColumn string) string {
+	return `(
+        SELECT COUNT(*) FROM pr_reviewers rr
+        JOIN prs rp ON rp.id = rr.pr_id AND rp.status = 'OPEN'
+        JOIN team_members rm ON rm.id = rr.user_id
+        WHERE rm.user_id = ` + userColumn + `
+    )`
+}
+
+// lockTeam берёт advisory-lock команды до конца транзакции, чтобы параллельные
+// назначения в одной команде не читали одну и ту же нагрузку ревьюверов.
+func lockTeam(ctx context.Context, tx pgx.Tx, teamName string) error {
+	_, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, teamName)
+	return err
+}
+
+// loadTeamSettings возвращает настройки команды; для команды без строки в team_settings - значения по умолчанию
+func loadTeamSettings(ctx context.Context, tx pgx.Tx, teamName string) (domain.TeamSettings, error) {
+	settings := domain.DefaultTeamSettings(teamName)
+	var strategy *string
+	err := tx.QueryRow(ctx, `
+        SELECT min_reviewers, max_reviewers, reviewer_strategy, auto_backfill,
+               min_approvals, block_on_changes_requested, block_on_need_more_reviewers
+        FROM team_settings WHERE team_name = $1
+    `, teamName).Scan(&settings.MinReviewers, &settings.MaxReviewers, &strategy, &settings.AutoBackfill,
+		&settings.MinApprovals, &settings.BlockOnChangesRequested, &settings.BlockOnNeedMoreReviewers)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return settings, err
+	}
+	if strategy != nil {
+		settings.ReviewerStrategy = domain.ReviewerStrategy(*strategy)
+	}
+	return settings, nil
+}
+
+// ensureTeamActive возвращает TEAM_ARCHIVED, если команда архивирована
+func ensureTeamActive(ctx context.Context, tx pgx.Tx, teamName string) error {
+	var archived bool
+	err := tx.QueryRow(ctx, `SELECT archived_at IS NOT NULL FROM teams WHERE name = $1`, teamName).Scan(&archived)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if archived {
+		return &errs.DomainError{Code: codes.TEAM_ARCHIVED}
+	}
+	return nil
+}
+
+// authorize проверяет, что инициатор - активный участник команды с ролью, для которой allowed возвращает true.
+// Администратор проходит без проверки.
+func authorize(ctx context.Context, tx pgx.Tx, teamName string, actor domain.Actor, allowed func(domain.TeamRole) bool) error {
+	if actor.Admin {
+		return nil
+	}
+	var role string
+	err := tx.QueryRow(ctx,
+		`SELECT role FROM team_members WHERE team_name = $1 AND user_id = $2 AND is_active = true`,
+		teamName, actor.UserID).Scan(&role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &errs.ForbiddenError{Desc: "actor is not an active member of team '" + teamName + "'"}
+	}
+	if err != nil {
+		return err
+	}
+	if !allowed(domain.TeamRole(role)) {
+		return &errs.ForbiddenError{Desc: "role '" + role + "' is not allowed to perform this action"}
+	}
+	return nil
+}
+
+// loadCandidates возвращает участников команды (кроме exclude), которых можно назначить ревьюверами,
+// в порядке добавления вместе с количеством их OPEN ревью в команде и временем последнего назначения.
+// Здесь собраны все ограничения на кандидатов: участник активен, не в периоде недоступности
+// и не набрал max_open_reviews OPEN ревью во всех своих командах.
+func loadCandidates(ctx context.Context, tx pgx.Tx, teamName string, exclude []int) ([]reviewer, []domain.ReviewerCandidate, error) {
+	if exclude == nil {
+		exclude = []int{}
+	}
+	rows, err := tx.Query(ctx, `
+        SELECT u.id, u.user_id, u.mandatory_reviewer, COUNT(p.id), MAX(prr.assigned_at),
+               usr.max_open_reviews - `+openReviewsSQL("u.user_id")+`
+        FROM team_members u
+        JOIN users usr ON usr.user_id = u.user_id
+        LEFT JOIN pr_reviewers prr ON prr.user_id = u.id
+        LEFT JOIN prs p ON p.id = prr.pr_id AND p.status = 'OPEN'
+        WHERE u.team_name = $1
+          AND u.is_active = true
+          AND u.id <> ALL($2::int[])
+          AND NOT EXISTS (
+              SELECT 1 FROM user_availability a
+              WHERE a.user_id = u.user_id AND a.starts_at <= now() AND a.ends_at > now()
+          )
+        GROUP BY u.id, u.user_id, u.mandatory_reviewer, usr.max_open_reviews
+        ORDER BY u.id
+    `, teamName, exclude)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var (
+		reviewers  []reviewer
+		candidates []domain.ReviewerCandidate
+	)
+	for rows.Next() {
+		var (
+			r              reviewer
+			c              domain.ReviewerCandidate
+			lastAssignedAt *time.Time
+			remaining      *int
+		)
+		if err := rows.Scan(&r.id, &r.userID, &r.mandatory, &c.OpenReviews, &lastAssignedAt, &remaining); err != nil {
+			return nil, nil, err
+		}
+		if remaining != nil {
+			if *remaining <= 0 {
+				continue
+			}
+			r.limited, r.remaining = true, *remaining
+		}
+		c.UserID = r.userID
+		if lastAssignedAt != nil {
+			c.LastAssignedAt = *lastAssignedAt
+		}
+		reviewers = append(reviewers, r)
+		candidates = append(candidates, c)
+	}
+	return reviewers, candidates, rows.Err()
+}
+
+// pickReviewers выбирает до n ревьюверов из команды стратегией команды.
+// Вызывающий должен держать lockTeam.
+func pickReviewers(ctx context.Context, tx pgx.Tx, settings domain.TeamSettings, exclude []int, n int, selectors *domain.ReviewerSelectors) ([]reviewer, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	reviewers, candidates, err := loadCandidates(ctx, tx, settings.TeamName, exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	byUserID := make(map[string]reviewer, len(reviewers))
+	for _, r := range reviewers {
+		byUserID[r.userID] = r
+	}
+	var picked []reviewer
+	for _, c := range selectors.For(settings.ReviewerStrategy).Select(candidates, n) {
+		picked = append(picked, byUserID[c.UserID])
+	}
+	return picked, nil
+}
+
+// pickInitialReviewers выбирает ревьюверов нового PR: сначала обязательных (лиды с mandatory_reviewer),
+// оставшиеся места до max_reviewers заполняются стратегией команды. Вызывающий должен держать lockTeam.
+func pickInitialReviewers(ctx context.Context, tx pgx.Tx, settings domain.TeamSettings, authorID int, selectors *domain.ReviewerSelectors) ([]reviewer, error) {
+	reviewers, _, err := loadCandidates(ctx, tx, settings.TeamName, []int{authorID})
+	if err != nil {
+		return nil, err
+	}
+	var picked []reviewer
+	for _, r := range reviewers {
+		if r.mandatory && len(picked) < settings.MaxReviewers {
+			picked = append(picked, r)
+		}
+	}
+
+	exclude := []int{authorID}
+	for _, r := range picked {
+		exclude = append(exclude, r.id)
+	}
+	rest, err := pickReviewers(ctx, tx, settings, exclude, settings.MaxReviewers-len(picked), selectors)
+	if err != nil {
+		return nil, err
+	}
+	return append(picked, rest...), nil
+}
+
+// pickReplacement ищет замену ревьюверу PR: активного участника команды, не автора
+// и не назначенного на PR. Возвращает nil, если кандидата нет. Вызывающий должен держать lockTeam.
+func pickReplacement(ctx context.Context, tx pgx.Tx, settings domain.TeamSettings, prID string, authorID int, selectors *domain.ReviewerSelectors) (*reviewer, error) {
+	exclude, err := reviewerIDs(ctx, tx, prID)
+	if err != nil {
+		return nil, err
+	}
+	picked, err := pickReviewers(ctx, tx, settings, append(exclude, authorID), 1, selectors)
+	if err != nil || len(picked) == 0 {
+		return nil, err
+	}
+	return &picked[0], nil
+}
+
+// pickTarget проверяет явно выбранную замену ревьювера: активный и доступный сейчас участник команды PR
+// со свободным местом под ревью, не автор и ещё не назначен на PR. Иначе возвращает NO_CANDIDATE.
+// Вызывающий должен держать lockTeam.
+func pickTarget(ctx context.Context, tx pgx.Tx, teamName, prID string, authorID int, userID string) (*reviewer, error) {
+	target := &reviewer{userID: userID}
+	var isActive, unavailable, atCapacity, assigned bool
+	err := tx.QueryRow(ctx, `
+        SELECT u.id, u.is_active,
+               EXISTS (
+                   SELECT 1 FROM user_availability a
+                   WHERE a.user_id = u.user_id AND a.starts_at <= now() AND a.ends_at > now()
+               ),
+               COALESCE(usr.max_open_reviews <= `+openReviewsSQL("u.user_id")+`, false),
+               EXISTS (SELECT 1 FROM pr_reviewers WHERE pr_id = $3 AND user_id = u.id)
+        FROM team_members u
+        JOIN users usr ON usr.user_id = u.user_id
+        WHERE u.team_name = $1 AND u.user_id = $2
+    `, teamName, userID, prID).Scan(&target.id, &isActive, &unavailable, &atCapacity, &assigned)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, &errs.DomainError{Code: codes.NO_CANDIDATE, Desc: "new reviewer is not a member of team '" + teamName + "'"}
+	case err != nil:
+		return nil, err
+	case !isActive:
+		return nil, &errs.DomainError{Code: codes.NO_CANDIDATE, Desc: "new reviewer is not active"}
+	case unavailable:
+		return nil, &errs.DomainError{Code: codes.NO_CANDIDATE, Desc: "new reviewer is unavailable now"}
+	case atCapacity:
+		return nil, &errs.DomainError{Code: codes.NO_CANDIDATE, Desc: "new reviewer has reached max_open_reviews"}
+	case target.id == authorID:
+		return nil, &errs.DomainError{Code: codes.NO_CANDIDATE, Desc: "author cannot review own PR"}
+	case assigned:
+		return nil, &errs.DomainError{Code: codes.NO_CANDIDATE, Desc: "new reviewer is already assigned to this PR"}
+	}
+	return target, nil
+}
+
+// swapReviewer снимает ревьювера old с PR и назначает candidate (если он есть), записывает замену
+// в историю PR от имени actorID с причиной reason и пересчитывает need_more_reviewers по min_reviewers команды.
+func swapReviewer(ctx context.Context, tx pgx.Tx, settings domain.TeamSettings, prID string, old reviewer, candidate *reviewer, actorID, reason string) (needMoreReviewers bool, err error) {
+	if _, err := tx.Exec(ctx,
+		`DELETE FROM pr_reviewers WHERE pr_id = $1 AND user_id = $2`, prID, old.id); err != nil {
+		return false, err
+	}
+	reassignment := domain.Reassignment{PrID: prID, OldReviewerID: old.userID}
+	if candidate != nil {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO pr_reviewers (pr_id, user_id, team_name) VALUES ($1, $2, $3)`,
+			prID, candidate.id, settings.TeamName); err != nil {
+			return false, err
+		}
+		reassignment.NewReviewerID = candidate.userID
+	}
+	if err := recordReassignment(ctx, tx, actorID, reason, reassignment); err != nil {
+		return false, err
+	}
+	return refreshNeedMoreReviewers(ctx, tx, settings, prID)
+}
+
+// refreshNeedMoreReviewers пересчитывает need_more_reviewers PR по min_reviewers команды
+func refreshNeedMoreReviewers(ctx context.Context, tx pgx.Tx, settings domain.TeamSettings, prID string) (needMoreReviewers bool, err error) {
+	err = tx.QueryRow(ctx, `
+        UPDATE prs
+        SET need_more_reviewers = (SELECT COUNT(*) FROM pr_reviewers WHERE pr_id = $1) < $2,
+            updated_at = $3
+        WHERE id = $1
+        RETURNING need_more_reviewers
+    `, prID, settings.MinReviewers, time.Now()).Scan(&needMoreReviewers)
+	return needMoreReviewers, err
+}
+
+// releaseReviews переназначает все OPEN ревью пользователя другим участникам команды.
+// Если замены нет, пользователь просто снимается с PR. Вызывающий должен держать lockTeam.
+func releaseReviews(ctx context.Context, tx pgx.Tx, settings domain.TeamSettings, userInternalID int, userID string, actorID string, selectors *domain.ReviewerSelectors) ([]domain.Reassignment, error) {
+	rows, err := tx.Query(ctx, `
+        SELECT p.id, p.author_id
+        FROM pr_reviewers prr
+        JOIN prs p ON p.id = prr.pr_id
+        WHERE prr.user_id = $1 AND p.status = 'OPEN'
+        ORDER BY p.created_at, p.id
+    `, userInternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	type review struct {
+		prID     string
+		authorID int
+	}
+	var reviews []review
+	for rows.Next() {
+		var r review
+		if err := rows.Scan(&r.prID, &r.authorID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		reviews = append(reviews, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	reassignments := make([]domain.Reassignment, 0, len(reviews))
+	for _, r := range reviews {
+		candidate, err := pickReplacement(ctx, tx, settings, r.prID, r.authorID, selectors)
+		if err != nil {
+			return nil, err
+		}
+		needMore, err := swapReviewer(ctx, tx, settings, r.prID, reviewer{id: userInternalID, userID: userID}, candidate, actorID, "")
+		if err != nil {
+			return nil, err
+		}
+		reassignment := domain.Reassignment{
+			PrID:              r.prID,
+			OldReviewerID:     userID,
+			NeedMoreReviewers: needMore,
+		}
+		if candidate != nil {
+			reassignment.NewReviewerID = candidate.userID
+		}
+		reassignments = append(reassignments, reassignment)
+	}
+	return reassignments, nil
+}
+
+// pendingReview - OPEN ревью, которое нужно переназначить
+type pendingReview struct {
+	prID     string
+	authorID int
+	oldID    int
+	oldUser  string
+}
+
+// distributeReviews подбирает замены сразу для набора ревью в памяти: нагрузка кандидатов
+// обновляется после каждого выбора, поэтому ревью расходятся по команде стратегией команды.
+// assigned - текущие ревьюверы каждого PR (внутренние id); обновляется по ходу распределения.
+// Возвращает замену для каждого ревью (nil - кандидата нет) в порядке pending.
+func distributeReviews(settings domain.TeamSettings, pending []pendingReview, assigned map[string]map[int]bool,
+	reviewers []reviewer, candidates []domain.ReviewerCandidate, selectors *domain.ReviewerSelectors) []*reviewer {
+	selector := selectors.For(settings.ReviewerStrategy)
+	now := time.Now()
+
+	picked := make([]*reviewer, len(pending))
+	for index, review := range pending {
+		eligible := make([]domain.ReviewerCandidate, 0, len(candidates))
+		positions := make([]int, 0, len(candidates))
+		for i, r := range reviewers {
+			if r.id == review.authorID || assigned[review.prID][r.id] || (r.limited && r.remaining <= 0) {
+				continue
+			}
+			eligible = append(eligible, candidates[i])
+			positions = append(positions, i)
+		}
+
+		delete(assigned[review.prID], review.oldID)
+		chosen := selector.Select(eligible, 1)
+		if len(chosen) == 0 {
+			continue
+		}
+		for j, c := range eligible {
+			if c.UserID != chosen[0].UserID {
+				continue
+			}
+			i := positions[j]
+			candidates[i].OpenReviews++
+			candidates[i].LastAssignedAt = now
+			reviewers[i].remaining--
+			picked[index] = &reviewers[i]
+			if assigned[review.prID] == nil {
+				assigned[review.prID] = make(map[int]bool)
+			}
+			assigned[review.prID][reviewers[i].id] = true
+			break
+		}
+	}
+	return picked
+}
+
+// reviewerIDs возвращает внутренние id всех ревьюверов PR
+func reviewerIDs(ctx context.Context, tx pgx.Tx, prID string) ([]int, error) {
+	rows, err := tx.Query(ctx, `SELECT user_id FROM pr_reviewers WHERE pr_id = $1`, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// backfillReviewer добавляет пользователя ревьювером во все OPEN PR команды с need_more_reviewers,
+// где он не автор, ещё не назначен и ревьюверов меньше max_reviewers. Возвращает id затронутых PR.
+// Недоступного сейчас пользователя не добирает, а с max_open_reviews - только до лимита.
+// Вызывающий должен держать lockTeam.
+func backfillReviewer(ctx context.Context, tx pgx.Tx, settings domain.TeamSettings, user reviewer, actorID string) ([]string, error) {
+	var (
+		unavailable bool
+		remaining   *int // nil - без лимита
+	)
+	if err := tx.QueryRow(ctx, `
+        SELECT EXISTS (
+                   SELECT 1 FROM user_availability
+                   WHERE user_id = $1 AND starts_at <= now() AND ends_at > now()
+               ),
+               usr.max_open_reviews - `+openReviewsSQL("usr.user_id")+`
+        FROM users usr
+        WHERE usr.user_id = $1
+    `, user.userID).Scan(&unavailable, &remaining); err != nil {
+		return nil, err
+	}
+	if unavailable || (remaining != nil && *remaining <= 0) {
+		return nil, nil
+	}
+
+	rows, err := tx.Query(ctx, `
+        SELECT p.id, COUNT(prr.user_id)
+        FROM prs p
+        LEFT JOIN pr_reviewers prr ON prr.pr_id = p.id
+        WHERE p.team_name = $1
+          AND p.status = 'OPEN'
+          AND p.need_more_reviewers = true
+          AND p.author_id <> $2
+          AND NOT EXISTS (
+              SELECT 1 FROM pr_reviewers WHERE pr_id = p.id AND user_id = $2
+          )
+        GROUP BY p.id, p.created_at
+        HAVING COUNT(prr.user_id) < $3
+        ORDER BY p.created_at, p.id
+        LIMIT $4
+    `, settings.TeamName, user.id, settings.MaxReviewers, remaining)
+	if err != nil {
+		return nil, err
+	}
+
+	type understaffed struct {
+		prID      string
+		reviewers int
+	}
+	var prs []understaffed
+	for rows.Next() {
+		var pr understaffed
+		if err := rows.Scan(&pr.prID, &pr.reviewers); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		prs = append(prs, pr)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	assigned := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO pr_reviewers (pr_id, user_id, team_name) VALUES ($1, $2, $3)`,
+			pr.prID, user.id, settings.TeamName); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(ctx,
+			`UPDATE prs SET need_more_reviewers = $1, updated_at = $2 WHERE id = $3`,
+			pr.reviewers+1 < settings.MinReviewers, now, pr.prID); err != nil {
+			return nil, err
+		}
+		if err := recordAssignments(ctx, tx, pr.prID, actorID, []reviewer{user}); err != nil {
+			return nil, err
+		}
+		assigned = append(assigned, pr.prID)
+	}
+	return assigned, nil
+}
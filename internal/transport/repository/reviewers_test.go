@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"pr-manage-service/internal/domain"
+	"testing"
+)
+
+func TestDistributeReviews(t *testing.T) {
+	settings := domain.DefaultTeamSettings("backend")
+	selectors := domain.NewReviewerSelectors(domain.LeastLoaded, 1)
+
+	// u1 и u2 деактивированы, их ревью должны разойтись по u3..u5
+	reviewers := []reviewer{{id: 3, userID: "u3"}, {id: 4, userID: "u4"}, {id: 5, userID: "u5"}}
+	candidates := []domain.ReviewerCandidate{
+		{UserID: "u3", OpenReviews: 0},
+		{UserID: "u4", OpenReviews: 0},
+		{UserID: "u5", OpenReviews: 5},
+	}
+	pending := []pendingReview{
+		{prID: "pr-1", authorID: 3, oldID: 1, oldUser: "u1"},
+		{prID: "pr-1", authorID: 3, oldID: 2, oldUser: "u2"},
+		{prID: "pr-2", authorID: 5, oldID: 1, oldUser: "u1"},
+		{prID: "pr-3", authorID: 4, oldID: 1, oldUser: "u1"},
+	}
+	assigned := map[string]map[int]bool{
+		"pr-1": {1: true, 2: true},
+		"pr-2": {1: true, 4: true},
+		"pr-3": {1: true, 3: true, 5: true},
+	}
+
+	picked := distributeReviews(settings, pending, assigned, reviewers, candidates, selectors)
+
+	want := []string{"u4", "u5", "u3", ""}
+	for index, r := range picked {
+		got := ""
+		if r != nil {
+			got = r.userID
+		}
+		if got != want[index] {
+			t.Errorf("review %d (%s/%s): got %q, want %q", index, pending[index].prID, pending[index].oldUser, got, want[index])
+		}
+	}
+	if candidates[0].OpenReviews != 1 || candidates[1].OpenReviews != 1 || candidates[2].OpenReviews != 6 {
+		t.Errorf("loads were not updated: %+v", candidates)
+	}
+	if assigned["pr-1"][1] || assigned["pr-1"][2] || !assigned["pr-1"][4] || !assigned["pr-1"][5] {
+		t.Errorf("pr-1 reviewers not updated: %v", assigned["pr-1"])
+	}
+}
+
+func TestDistributeReviewsCapacity(t *testing.T) {
+	settings := domain.DefaultTeamSettings("backend")
+	selectors := domain.NewReviewerSelectors(domain.LeastLoaded, 1)
+
+	// у u3 осталось одно место под ревью, поэтому второе ревью уходит u4, хотя он загружен больше
+	reviewers := []reviewer{{id: 3, userID: "u3", limited: true, remaining: 1}, {id: 4, userID: "u4"}}
+	candidates := []domain.ReviewerCandidate{
+		{UserID: "u3", OpenReviews: 0},
+		{UserID: "u4", OpenReviews: 4},
+	}
+	pending := []pendingReview{
+		{prID: "pr-1", authorID: 9, oldID: 1, oldUser: "u1"},
+		{prID: "pr-2", authorID: 9, oldID: 1, oldUser: "u1"},
+		{prID: "pr-3", authorID: 4, oldID: 1, oldUser: "u1"},
+	}
+	assigned := map[string]map[int]bool{
+		"pr-1": {1: true},
+		"pr-2": {1: true},
+		"pr-3": {1: true},
+	}
+
+	picked := distributeReviews(settings, pending, assigned, reviewers, candidates, selectors)
+
+	want := []string{"u3", "u4", ""}
+	for index, r := range picked {
+		got := ""
+		if r != nil {
+			got = r.userID
+		}
+		if got != want[index] {
+			t.Errorf("review %d (%s): got %q, want %q", index, pending[index].prID, got, want[index])
+		}
+	}
+}
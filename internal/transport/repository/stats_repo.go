@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"pr-manage-service/internal/domain"
+	"pr-manage-service/pkg/errs"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+)
+
+type statsRepository struct {
+	ctx      context.Context
+	pool     *pgxpool.Pool
+	rtimeout time.Duration
+}
+
+func NewStatsRepository(ctx context.Context, pool *pgxpool.Pool, rtimeout time.Duration) domain.StatsRepository {
+	return &statsRepository{
+		ctx:      ctx,
+		pool:     pool,
+		rtimeout: rtimeout,
+	}
+}
+
+// GetStats implements domain.StatsRepository.
+func (s *statsRepository) GetStats(filter domain.StatsFilter) (*domain.Stats, error) {
+	reqCtx, cancel := context.WithTimeout(s.ctx, s.rtimeout)
+	defer cancel()
+
+	// Все показатели читаем из одного снимка данных
+	tx, err := s.pool.BeginTx(reqCtx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	defer tx.Rollback(reqCtx)
+
+	stats := &domain.Stats{}
+
+	// Назначения на ревью по пользователям (включая тех, у кого их нет)
+	if stats.ReviewAssignments, err = scanUserCounts(reqCtx, tx, `
+        SELECT u.user_id, u.team_name, COUNT(prr.pr_id)
+        FROM team_members u
+        LEFT JOIN pr_reviewers prr ON prr.user_id = u.id
+            AND ($2::timestamptz IS NULL OR prr.assigned_at >= $2)
+            AND ($3::timestamptz IS NULL OR prr.assigned_at < $3)
+        WHERE u.team_name IS NOT NULL AND ($1 = '' OR u.team_name = $1)
+        GROUP BY u.id, u.user_id, u.team_name
+        ORDER BY COUNT(prr.pr_id) DESC, u.team_name, u.user_id
+    `, filter.TeamName, filter.From, filter.To); err != nil {
+		logrus.Error(logPrefix, "(review assignments) error:", err.Error())
+		return nil, &errs.InternalError{}
+	}
+
+	// OPEN и MERGED PR по командам
+	rows, err := tx.Query(reqCtx, `
+        SELECT t.name,
+               COUNT(p.id) FILTER (WHERE p.status = 'OPEN'),
+               COUNT(p.id) FILTER (WHERE p.status = 'MERGED')
+        FROM teams t
+        LEFT JOIN prs p ON p.team_name = t.name
+            AND ($2::timestamptz IS NULL OR p.created_at >= $2)
+            AND ($3::timestamptz IS NULL OR p.created_at < $3)
+        WHERE ($1 = '' OR t.name = $1)
+        GROUP BY t.name
+        ORDER BY t.name
+    `, filter.TeamName, filter.From, filter.To)
+	if err != nil {
+		logrus.Error(logPrefix, "(team prs) error:", err.Error())
+		return nil, &errs.InternalError{}
+	}
+	for rows.Next() {
+		var team domain.TeamPRCount
+		if err := rows.Scan(&team.TeamName, &team.Open, &team.Merged); err != nil {
+			rows.Close()
+			logrus.Error(logPrefix, err.Error())
+			return nil, &errs.InternalError{}
+		}
+		stats.TeamPRs = append(stats.TeamPRs, team)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+
+	// PR по авторам
+	if stats.AuthorPRs, err = scanUserCounts(reqCtx, tx, `
+        SELECT author.user_id, p.team_name, COUNT(p.id)
+        FROM prs p
+        JOIN team_members author ON author.id = p.author_id
+        WHERE ($1 = '' OR p.team_name = $1)
+          AND ($2::timestamptz IS NULL OR p.created_at >= $2)
+          AND ($3::timestamptz IS NULL OR p.created_at < $3)
+        GROUP BY author.id, author.user_id, p.team_name
+        ORDER BY COUNT(p.id) DESC, p.team_name, author.user_id
+    `, filter.TeamName, filter.From, filter.To); err != nil {
+		logrus.Error(logPrefix, "(author prs) error:", err.Error())
+		return nil, &errs.InternalError{}
+	}
+
+	// OPEN PR с нехваткой ревьюверов
+	if err := tx.QueryRow(reqCtx, `
+        SELECT COUNT(*)
+        FROM prs p
+        WHERE p.status = 'OPEN' AND p.need_more_reviewers = true
+          AND ($1 = '' OR p.team_name = $1)
+          AND ($2::timestamptz IS NULL OR p.created_at >= $2)
+          AND ($3::timestamptz IS NULL OR p.created_at < $3)
+    `, filter.TeamName, filter.From, filter.To).Scan(&stats.NeedMoreReviewers); err != nil {
+		logrus.Error(logPrefix, "(need more reviewers) error:", err.Error())
+		return nil, &errs.InternalError{}
+	}
+
+	return stats, nil
+}
+
+func scanUserCounts(ctx context.Context, tx pgx.Tx, sql string, args ...any) ([]domain.UserCount, error) {
+	rows, err := tx.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []domain.UserCount
+	for rows.Next() {
+		var c domain.UserCount
+		if err := rows.Scan(&c.UserID, &c.TeamName, &c.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
@@ -3,7 +3,9 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"pr-manage-service/internal/domain"
+	"pr-manage-service/pkg/codes"
 	"pr-manage-service/pkg/errs"
 	"strings"
 	"sync"
@@ -60,9 +62,12 @@ func (t *teamRepository) AddNewTeam(teamName string, members *[]domain.User) err
 		batch := &pgx.Batch{}
 
 		for _, member := range *members {
+			batch.Queue(upsertUserSQL, member.UserID, member.Login, member.UserName, member.MaxOpenReviews)
 			batch.Queue(
-				`INSERT INTO users (user_id, team_name, name, is_active) VALUES ($1, $2, $3, $4)`,
-				member.UserID, teamName, member.UserName, member.IsActive,
+				`INSERT INTO team_members (user_id, team_name, is_active, role, mandatory_reviewer) VALUES ($1, $2, $3, $4, $5)
+				ON CONFLICT (team_name, user_id) DO UPDATE
+				SET is_active = EXCLUDED.is_active, role = EXCLUDED.role, mandatory_reviewer = EXCLUDED.mandatory_reviewer`,
+				member.UserID, teamName, member.IsActive, memberRole(member), member.MandatoryReviewer,
 			)
 		}
 
@@ -74,6 +79,9 @@ func (t *teamRepository) AddNewTeam(teamName string, members *[]domain.User) err
 		for i := 0; i < batch.Len(); i++ {
 			_, err := br.Exec()
 			if err != nil {
+				if strings.Contains(err.Error(), "users_login_key") {
+					return &errs.DomainError{Code: codes.USER_EXISTS, Desc: "login is already taken by another user"}
+				}
 				logrus.Error(logPrefix, "(batch exec) error at query %d: %v\n", i, err)
 				return &errs.InternalError{}
 			}
@@ -115,7 +123,7 @@ func (t *teamRepository) GetTeamInfoByName(teamName string) (*domain.Team, error
 		return nil, err
 	}
 	var team domain.Team = domain.Team{Members: make([]domain.User, 0, 10)}
-	row := tx.QueryRow(reqCtx, `SELECT name FROM teams WHERE name=$1`, teamName)
+	row := tx.QueryRow(reqCtx, `SELECT name FROM teams WHERE name=$1 AND archived_at IS NULL`, teamName)
 	if err := row.Scan(&team.TeamName); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, &errs.NotFoundError{
@@ -127,8 +135,12 @@ func (t *teamRepository) GetTeamInfoByName(teamName string) (*domain.Team, error
 	}
 
 	rows, err := tx.Query(reqCtx,
-		`SELECT user_id, name, is_active FROM users 
-		WHERE team_name = $1`, teamName)
+		`SELECT m.user_id, u.login, u.name, m.is_active, m.role, m.mandatory_reviewer,
+		        u.max_open_reviews, `+openReviewsSQL("u.user_id")+`
+		FROM team_members m
+		JOIN users u ON u.user_id = m.user_id
+		WHERE m.team_name = $1
+		ORDER BY m.id`, teamName)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return &team, nil
@@ -138,12 +150,456 @@ func (t *teamRepository) GetTeamInfoByName(teamName string) (*domain.Team, error
 	}
 	for rows.Next() {
 		var user domain.User
-		if err := rows.Scan(&user.UserID, &user.UserName, &user.IsActive); err != nil {
+		var role string
+		if err := rows.Scan(&user.UserID, &user.Login, &user.UserName, &user.IsActive, &role, &user.MandatoryReviewer,
+			&user.MaxOpenReviews, &user.OpenReviews); err != nil {
 			logrus.Error(logPrefix, "(row scan) error:", err.Error())
 			continue
 		}
+		user.Role = domain.TeamRole(role)
 		team.Members = append(team.Members, user)
 	}
 
 	return &team, nil
 }
+
+// GetSettings implements domain.TeamRepository.
+func (t *teamRepository) GetSettings(teamName string) (*domain.TeamSettings, error) {
+	reqCtx, cancel := context.WithTimeout(t.ctx, t.rtimeout)
+	defer cancel()
+
+	tx, err := t.pool.Begin(reqCtx)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	defer tx.Rollback(reqCtx)
+
+	var exists bool
+	if err := tx.QueryRow(reqCtx, `SELECT EXISTS (SELECT 1 FROM teams WHERE name = $1 AND archived_at IS NULL)`, teamName).Scan(&exists); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	if !exists {
+		return nil, &errs.NotFoundError{Domain: "team"}
+	}
+
+	settings, err := loadTeamSettings(reqCtx, tx, teamName)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	return &settings, nil
+}
+
+// SaveSettings implements domain.TeamRepository.
+// Политику ревью и merge меняет только тот, кто управляет составом команды.
+func (t *teamRepository) SaveSettings(settings *domain.TeamSettings, actor domain.Actor) error {
+	reqCtx, cancel := context.WithTimeout(t.ctx, t.rtimeout)
+	defer cancel()
+
+	tx, err := t.pool.Begin(reqCtx)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return &errs.InternalError{}
+	}
+	defer tx.Rollback(reqCtx)
+
+	if err := authorize(reqCtx, tx, settings.TeamName, actor, domain.TeamRole.CanManageMembers); err != nil {
+		return prLoadError(err)
+	}
+
+	var strategy *string
+	if settings.ReviewerStrategy != "" {
+		s := string(settings.ReviewerStrategy)
+		strategy = &s
+	}
+	if _, err := tx.Exec(reqCtx, `
+        INSERT INTO team_settings (team_name, min_reviewers, max_reviewers, reviewer_strategy, auto_backfill,
+                                   min_approvals, block_on_changes_requested, block_on_need_more_reviewers)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        ON CONFLICT (team_name) DO UPDATE
+        SET min_reviewers = EXCLUDED.min_reviewers,
+            max_reviewers = EXCLUDED.max_reviewers,
+            reviewer_strategy = EXCLUDED.reviewer_strategy,
+            auto_backfill = EXCLUDED.auto_backfill,
+            min_approvals = EXCLUDED.min_approvals,
+            block_on_changes_requested = EXCLUDED.block_on_changes_requested,
+            block_on_need_more_reviewers = EXCLUDED.block_on_need_more_reviewers
+    `, settings.TeamName, settings.MinReviewers, settings.MaxReviewers, strategy, settings.AutoBackfill,
+		settings.MinApprovals, settings.BlockOnChangesRequested, settings.BlockOnNeedMoreReviewers); err != nil {
+		if strings.Contains(err.Error(), "foreign key") {
+			return &errs.NotFoundError{Domain: "team"}
+		}
+		logrus.Error(logPrefix, "(upsert settings) error: ", err.Error())
+		return &errs.InternalError{}
+	}
+
+	if err := tx.Commit(reqCtx); err != nil {
+		logrus.Error(logPrefix, "(tx.commit) error: ", err.Error())
+		return &errs.InternalError{}
+	}
+	return nil
+}
+
+// UpsertMembers implements domain.TeamRepository.
+func (t *teamRepository) UpsertMembers(teamName string, members []domain.User, actor domain.Actor, selectors *domain.ReviewerSelectors) (*domain.MembersChange, error) {
+	reqCtx, cancel := context.WithTimeout(t.ctx, t.rtimeout)
+	defer cancel()
+
+	tx, err := t.pool.Begin(reqCtx)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	defer tx.Rollback(reqCtx)
+
+	if err := lockExistingTeam(reqCtx, tx, teamName); err != nil {
+		return nil, err
+	}
+	authorized := false
+	if domain.SetsRoles(members) {
+		if err := authorize(reqCtx, tx, teamName, actor, domain.TeamRole.CanManageMembers); err != nil {
+			return nil, prLoadError(err)
+		}
+		authorized = true
+	}
+	settings, err := loadTeamSettings(reqCtx, tx, teamName)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+
+	// Сначала применяем все изменения, чтобы ревью уходили только участникам, активным после обновления
+	change := &domain.MembersChange{Added: []string{}, Updated: []string{}}
+	var activated, deactivated []reviewer
+	for _, member := range members {
+		var (
+			internalID int
+			wasActive  bool
+		)
+		if err := upsertUser(reqCtx, tx, member); err != nil {
+			return nil, err
+		}
+		err := tx.QueryRow(reqCtx,
+			`SELECT id, is_active FROM team_members WHERE team_name = $1 AND user_id = $2`,
+			teamName, member.UserID).Scan(&internalID, &wasActive)
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			if err := tx.QueryRow(reqCtx,
+				`INSERT INTO team_members (user_id, team_name, is_active, role, mandatory_reviewer) VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+				member.UserID, teamName, member.IsActive, memberRole(member), member.MandatoryReviewer).Scan(&internalID); err != nil {
+				logrus.Error(logPrefix, "(insert member) error: ", err.Error())
+				return nil, &errs.InternalError{}
+			}
+			change.Added = append(change.Added, member.UserID)
+			if member.IsActive {
+				activated = append(activated, reviewer{id: internalID, userID: member.UserID})
+			}
+		case err != nil:
+			logrus.Error(logPrefix, err.Error())
+			return nil, &errs.InternalError{}
+		default:
+			// Активировать и деактивировать существующих участников могут только те, кто управляет составом
+			if wasActive != member.IsActive && !authorized {
+				if err := authorize(reqCtx, tx, teamName, actor, domain.TeamRole.CanManageMembers); err != nil {
+					return nil, prLoadError(err)
+				}
+				authorized = true
+			}
+			if _, err := tx.Exec(reqCtx,
+				`UPDATE team_members SET is_active = $1, role = COALESCE(NULLIF($2, ''), role),
+                 mandatory_reviewer = CASE WHEN $2 = '' THEN mandatory_reviewer ELSE $3 END WHERE id = $4`,
+				member.IsActive, string(member.Role), member.MandatoryReviewer, internalID); err != nil {
+				logrus.Error(logPrefix, "(update member) error: ", err.Error())
+				return nil, &errs.InternalError{}
+			}
+			change.Updated = append(change.Updated, member.UserID)
+			if wasActive && !member.IsActive {
+				deactivated = append(deactivated, reviewer{id: internalID, userID: member.UserID})
+			} else if !wasActive && member.IsActive {
+				activated = append(activated, reviewer{id: internalID, userID: member.UserID})
+			}
+		}
+	}
+
+	for _, r := range deactivated {
+		reassignments, err := releaseReviews(reqCtx, tx, settings, r.id, r.userID, "", selectors)
+		if err != nil {
+			logrus.Error(logPrefix, "(release reviews) error:", err.Error())
+			return nil, &errs.InternalError{}
+		}
+		change.Reassignments = append(change.Reassignments, reassignments...)
+	}
+	if settings.AutoBackfill {
+		// Один PR может добрать нескольких участников, в ответе он указывается один раз
+		seen := map[string]bool{}
+		for _, r := range activated {
+			assigned, err := backfillReviewer(reqCtx, tx, settings, r, "")
+			if err != nil {
+				logrus.Error(logPrefix, "(backfill) error:", err.Error())
+				return nil, &errs.InternalError{}
+			}
+			for _, prID := range assigned {
+				if !seen[prID] {
+					seen[prID] = true
+					change.AssignedPRs = append(change.AssignedPRs, prID)
+				}
+			}
+		}
+	}
+
+	if err := tx.Commit(reqCtx); err != nil {
+		logrus.Error(logPrefix, "(tx.commit) error: ", err.Error())
+		return nil, &errs.InternalError{}
+	}
+	return change, nil
+}
+
+// RemoveMember implements domain.TeamRepository.
+// Участник не удаляется физически: его PR и история ревью остаются, а сам он отвязывается от команды.
+func (t *teamRepository) RemoveMember(teamName string, userID string, actor domain.Actor, selectors *domain.ReviewerSelectors) ([]domain.Reassignment, error) {
+	reqCtx, cancel := context.WithTimeout(t.ctx, t.rtimeout)
+	defer cancel()
+
+	tx, err := t.pool.Begin(reqCtx)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	defer tx.Rollback(reqCtx)
+
+	if err := lockExistingTeam(reqCtx, tx, teamName); err != nil {
+		return nil, err
+	}
+	if err := authorize(reqCtx, tx, teamName, actor, domain.TeamRole.CanManageMembers); err != nil {
+		return nil, prLoadError(err)
+	}
+
+	var internalID int
+	if err := tx.QueryRow(reqCtx, `
+        UPDATE team_members SET team_name = NULL, is_active = false
+        WHERE team_name = $1 AND user_id = $2
+        RETURNING id
+    `, teamName, userID).Scan(&internalID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &errs.NotFoundError{Domain: "user"}
+		}
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+
+	settings, err := loadTeamSettings(reqCtx, tx, teamName)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	reassignments, err := releaseReviews(reqCtx, tx, settings, internalID, userID, "", selectors)
+	if err != nil {
+		logrus.Error(logPrefix, "(release reviews) error:", err.Error())
+		return nil, &errs.InternalError{}
+	}
+
+	if err := tx.Commit(reqCtx); err != nil {
+		logrus.Error(logPrefix, "(tx.commit) error: ", err.Error())
+		return nil, &errs.InternalError{}
+	}
+	return reassignments, nil
+}
+
+// RenameMember implements domain.TeamRepository.
+func (t *teamRepository) RenameMember(teamName string, userID string, userName string, actor domain.Actor) (*domain.User, error) {
+	reqCtx, cancel := context.WithTimeout(t.ctx, t.rtimeout)
+	defer cancel()
+
+	tx, err := t.pool.Begin(reqCtx)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	defer tx.Rollback(reqCtx)
+
+	if err := authorize(reqCtx, tx, teamName, actor, domain.TeamRole.CanManageMembers); err != nil {
+		return nil, prLoadError(err)
+	}
+
+	// Имя глобальное: оно меняется во всех командах пользователя
+	user := &domain.User{UserID: userID, UserName: userName, TeamName: teamName}
+	if err := tx.QueryRow(reqCtx, `
+        UPDATE users u SET name = $3
+        FROM team_members m
+        WHERE m.user_id = u.user_id AND m.team_name = $1 AND u.user_id = $2
+        RETURNING u.login, m.is_active
+    `, teamName, userID, userName).Scan(&user.Login, &user.IsActive); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &errs.NotFoundError{Domain: "user"}
+		}
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+
+	if err := tx.Commit(reqCtx); err != nil {
+		logrus.Error(logPrefix, "(tx.commit) error: ", err.Error())
+		return nil, &errs.InternalError{}
+	}
+	return user, nil
+}
+
+// memberRole возвращает роль нового участника; без явной роли - member
+func memberRole(member domain.User) string {
+	if member.Role == "" {
+		return string(domain.RoleMember)
+	}
+	return string(member.Role)
+}
+
+// upsertUserSQL создаёт глобального пользователя или обновляет его имя.
+// Пустой login при создании заменяется на user_id, а при обновлении сохраняет текущий.
+// NULL в max_open_reviews сохраняет текущий лимит, 0 снимает его.
+const upsertUserSQL = `
+        INSERT INTO users (user_id, login, name, max_open_reviews)
+        VALUES ($1, COALESCE(NULLIF($2, ''), $1), $3, NULLIF($4::int, 0))
+        ON CONFLICT (user_id) DO UPDATE
+        SET name = EXCLUDED.name,
+            login = CASE WHEN $2 = '' THEN users.login ELSE EXCLUDED.login END,
+            max_open_reviews = CASE WHEN $4::int IS NULL THEN users.max_open_reviews ELSE NULLIF($4::int, 0) END`
+
+// upsertUser создаёт или обновляет глобального пользователя; занятый login - USER_EXISTS
+func upsertUser(ctx context.Context, tx pgx.Tx, user domain.User) error {
+	if _, err := tx.Exec(ctx, upsertUserSQL, user.UserID, user.Login, user.UserName, user.MaxOpenReviews); err != nil {
+		if strings.Contains(err.Error(), "users_login_key") {
+			return &errs.DomainError{Code: codes.USER_EXISTS, Desc: "login '" + user.Login + "' is already taken by another user"}
+		}
+		logrus.Error(logPrefix, "(upsert user) error: ", err.Error())
+		return &errs.InternalError{}
+	}
+	return nil
+}
+
+// lockExistingTeam проверяет, что команда существует и не архивирована, и берёт lockTeam
+func lockExistingTeam(ctx context.Context, tx pgx.Tx, teamName string) error {
+	var archived bool
+	if err := tx.QueryRow(ctx, `SELECT archived_at IS NOT NULL FROM teams WHERE name = $1`, teamName).Scan(&archived); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &errs.NotFoundError{Domain: "team"}
+		}
+		logrus.Error(logPrefix, err.Error())
+		return &errs.InternalError{}
+	}
+	if archived {
+		return &errs.DomainError{Code: codes.TEAM_ARCHIVED, Desc: "team is archived"}
+	}
+	if err := lockTeam(ctx, tx, teamName); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return &errs.InternalError{}
+	}
+	return nil
+}
+
+// DeleteTeam implements domain.TeamRepository.
+func (t *teamRepository) DeleteTeam(teamName string, mode domain.TeamDeleteMode, force bool) (openPRs int, err error) {
+	reqCtx, cancel := context.WithTimeout(t.ctx, t.rtimeout)
+	defer cancel()
+
+	tx, err := t.pool.Begin(reqCtx)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return 0, &errs.InternalError{}
+	}
+	defer tx.Rollback(reqCtx)
+
+	// Блокируем строку команды: параллельные операции с участниками и PR ждут удаления
+	var archived bool
+	if err := tx.QueryRow(reqCtx,
+		`SELECT archived_at IS NOT NULL FROM teams WHERE name = $1 FOR UPDATE`,
+		teamName).Scan(&archived); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, &errs.NotFoundError{Domain: "team"}
+		}
+		logrus.Error(logPrefix, err.Error())
+		return 0, &errs.InternalError{}
+	}
+	if err := lockTeam(reqCtx, tx, teamName); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return 0, &errs.InternalError{}
+	}
+
+	if err := tx.QueryRow(reqCtx,
+		`SELECT COUNT(*) FROM prs WHERE team_name = $1 AND status = 'OPEN'`,
+		teamName).Scan(&openPRs); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return 0, &errs.InternalError{}
+	}
+	// Повторная архивация ничего не меняет: OPEN PR архивной команды заморожены, а не закрыты
+	if mode == domain.DeleteArchive && archived {
+		return openPRs, nil
+	}
+	if openPRs > 0 && !force {
+		return openPRs, &errs.DomainError{
+			Code: codes.TEAM_HAS_OPEN,
+			Desc: fmt.Sprintf("team has %d OPEN PRs, use force to delete anyway", openPRs),
+		}
+	}
+
+	switch mode {
+	case domain.DeleteHard:
+		// Участники, их PR, назначения и решения удаляются каскадно
+		if _, err := tx.Exec(reqCtx, `DELETE FROM teams WHERE name = $1`, teamName); err != nil {
+			logrus.Error(logPrefix, "(delete team) error: ", err.Error())
+			return 0, &errs.InternalError{}
+		}
+	case domain.DeleteArchive:
+		if _, err := tx.Exec(reqCtx, `UPDATE teams SET archived_at = $1 WHERE name = $2`, time.Now(), teamName); err != nil {
+			logrus.Error(logPrefix, "(archive team) error: ", err.Error())
+			return 0, &errs.InternalError{}
+		}
+	}
+
+	if err := tx.Commit(reqCtx); err != nil {
+		logrus.Error(logPrefix, "(tx.commit) error: ", err.Error())
+		return 0, &errs.InternalError{}
+	}
+	return openPRs, nil
+}
+
+// likeEscaper экранирует спецсимволы LIKE в пользовательском префиксе
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// ListTeams implements domain.TeamRepository.
+func (t *teamRepository) ListTeams(filter domain.TeamListFilter) ([]domain.TeamSummary, error) {
+	reqCtx, cancel := context.WithTimeout(t.ctx, t.rtimeout)
+	defer cancel()
+
+	rows, err := t.pool.Query(reqCtx, `
+        SELECT t.name,
+               (SELECT COUNT(*) FROM team_members u WHERE u.team_name = t.name),
+               (SELECT COUNT(*) FROM team_members u WHERE u.team_name = t.name AND u.is_active = true),
+               (SELECT COUNT(*) FROM prs p WHERE p.team_name = t.name AND p.status = 'OPEN')
+        FROM teams t
+        WHERE t.archived_at IS NULL
+          AND t.name LIKE $1 || '%'
+          AND t.name > $2
+        ORDER BY t.name
+        LIMIT $3
+    `, likeEscaper.Replace(filter.Prefix), filter.After, filter.Limit)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	defer rows.Close()
+
+	teams := make([]domain.TeamSummary, 0, filter.Limit)
+	for rows.Next() {
+		var team domain.TeamSummary
+		if err := rows.Scan(&team.TeamName, &team.Members, &team.ActiveMembers, &team.OpenPRs); err != nil {
+			logrus.Error(logPrefix, err.Error())
+			return nil, &errs.InternalError{}
+		}
+		teams = append(teams, team)
+	}
+	if err := rows.Err(); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	return teams, nil
+}
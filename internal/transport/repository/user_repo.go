@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"pr-manage-service/internal/domain"
+	"pr-manage-service/pkg/codes"
 	"pr-manage-service/pkg/errs"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -27,40 +30,598 @@ func NewUserRepository(ctx context.Context, pool *pgxpool.Pool, rtimeout time.Du
 }
 
 // ChangeActive implements domain.UserRepository.
-func (u *userRepository) ChangeActive(teamName string, userID string, isActive bool) (name string, err error) {
+func (u *userRepository) ChangeActive(teamName string, userID string, isActive bool, actor domain.Actor, selectors *domain.ReviewerSelectors) (*domain.ActiveChange, error) {
 	reqCtx, cancel := context.WithTimeout(u.ctx, u.rtimeout)
 	defer cancel()
 
 	tx, err := u.pool.Begin(reqCtx)
 	if err != nil {
 		logrus.Error(logPrefix, err.Error())
-		return "", err
+		return nil, err
 	}
 	defer tx.Rollback(reqCtx)
 
-	var username string
+	if teamName == "" {
+		if teamName, err = resolveMemberTeam(reqCtx, tx, userID); err != nil {
+			return nil, err
+		}
+	}
+	if err := authorize(reqCtx, tx, teamName, actor, domain.TeamRole.CanManageMembers); err != nil {
+		return nil, prLoadError(err)
+	}
+
+	var internalID int
 	var user_active_state bool
-	if err := tx.QueryRow(reqCtx, `SELECT name, is_active FROM users WHERE user_id=$1 AND team_name=$2`, userID, teamName).Scan(&username, &user_active_state); err != nil {
+	change := &domain.ActiveChange{TeamName: teamName}
+	if err := tx.QueryRow(reqCtx, `
+        SELECT m.id, u.name, m.is_active FROM team_members m
+        JOIN users u ON u.user_id = m.user_id
+        WHERE m.user_id=$1 AND m.team_name=$2
+    `, userID, teamName).Scan(&internalID, &change.UserName, &user_active_state); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return "", &errs.NotFoundError{
+			return nil, &errs.NotFoundError{
 				Domain: "user",
 			}
 		}
 		logrus.Error(logPrefix, err.Error())
-		return "", &errs.InternalError{}
+		return nil, &errs.InternalError{}
 	}
 
 	if user_active_state != isActive {
-		if _, err := tx.Exec(reqCtx, `UPDATE users SET is_active = $1 WHERE team_name=$2 AND user_id=$3`, isActive, teamName, userID); err != nil {
+		if _, err := tx.Exec(reqCtx, `UPDATE team_members SET is_active = $1 WHERE team_name=$2 AND user_id=$3`, isActive, teamName, userID); err != nil {
 			logrus.Error(logPrefix, "(update) error:", err.Error())
-			return "", &errs.InternalError{}
+			return nil, &errs.InternalError{}
+		}
+
+		settings, err := loadTeamSettings(reqCtx, tx, teamName)
+		if err != nil {
+			logrus.Error(logPrefix, err.Error())
+			return nil, &errs.InternalError{}
+		}
+		if err := lockTeam(reqCtx, tx, teamName); err != nil {
+			logrus.Error(logPrefix, err.Error())
+			return nil, &errs.InternalError{}
+		}
+		if err := ensureTeamActive(reqCtx, tx, teamName); err != nil {
+			return nil, prLoadError(err)
+		}
+
+		if isActive {
+			// Вернувшегося участника добираем в PR с нехваткой ревьюверов, если команда это включила
+			if settings.AutoBackfill {
+				if change.AssignedPRs, err = backfillReviewer(reqCtx, tx, settings, reviewer{id: internalID, userID: userID}, actor.UserID); err != nil {
+					logrus.Error(logPrefix, "(backfill) error:", err.Error())
+					return nil, &errs.InternalError{}
+				}
+			}
+		} else {
+			// OPEN ревью деактивированного участника переходят другим участникам команды
+			if change.Reassignments, err = releaseReviews(reqCtx, tx, settings, internalID, userID, actor.UserID, selectors); err != nil {
+				logrus.Error(logPrefix, "(release reviews) error:", err.Error())
+				return nil, &errs.InternalError{}
+			}
 		}
 	}
 
 	if err := tx.Commit(reqCtx); err != nil {
 		logrus.Error(err.Error())
-		return "", err
+		return nil, err
+	}
+
+	return change, nil
+}
+
+// BulkDeactivate implements domain.UserRepository.
+func (u *userRepository) BulkDeactivate(teamName string, userIDs []string, actor domain.Actor, selectors *domain.ReviewerSelectors) ([]domain.Reassignment, error) {
+	reqCtx, cancel := context.WithTimeout(u.ctx, u.rtimeout)
+	defer cancel()
+
+	tx, err := u.pool.Begin(reqCtx)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	defer tx.Rollback(reqCtx)
+
+	if err := lockTeam(reqCtx, tx, teamName); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	if err := ensureTeamActive(reqCtx, tx, teamName); err != nil {
+		return nil, prLoadError(err)
+	}
+	if err := authorize(reqCtx, tx, teamName, actor, domain.TeamRole.CanManageMembers); err != nil {
+		return nil, prLoadError(err)
+	}
+	settings, err := loadTeamSettings(reqCtx, tx, teamName)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+
+	// 1. Деактивируем всех пользователей одним запросом
+	rows, err := tx.Query(reqCtx, `
+        UPDATE team_members SET is_active = false
+        WHERE team_name = $1 AND user_id = ANY($2)
+        RETURNING id, user_id
+    `, teamName, userIDs)
+	if err != nil {
+		logrus.Error(logPrefix, "(deactivate) error:", err.Error())
+		return nil, &errs.InternalError{}
+	}
+	deactivated := make(map[string]int, len(userIDs))
+	var internalIDs []int
+	for rows.Next() {
+		var id int
+		var userID string
+		if err := rows.Scan(&id, &userID); err != nil {
+			rows.Close()
+			logrus.Error(logPrefix, err.Error())
+			return nil, &errs.InternalError{}
+		}
+		deactivated[userID] = id
+		internalIDs = append(internalIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	for _, userID := range userIDs {
+		if _, ok := deactivated[userID]; !ok {
+			return nil, &errs.NotFoundError{Domain: "user", Desc: userID}
+		}
+	}
+
+	// 2. Все OPEN ревью деактивированных и текущие ревьюверы этих PR
+	rows, err = tx.Query(reqCtx, `
+        SELECT prr.pr_id, p.author_id, prr.user_id, u.user_id
+        FROM pr_reviewers prr
+        JOIN prs p ON p.id = prr.pr_id
+        JOIN team_members u ON u.id = prr.user_id
+        WHERE prr.user_id = ANY($1) AND p.status = 'OPEN'
+        ORDER BY p.created_at, p.id, prr.user_id
+    `, internalIDs)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	var pending []pendingReview
+	var prIDs []string
+	seen := make(map[string]bool)
+	for rows.Next() {
+		var review pendingReview
+		if err := rows.Scan(&review.prID, &review.authorID, &review.oldID, &review.oldUser); err != nil {
+			rows.Close()
+			logrus.Error(logPrefix, err.Error())
+			return nil, &errs.InternalError{}
+		}
+		pending = append(pending, review)
+		if !seen[review.prID] {
+			seen[review.prID] = true
+			prIDs = append(prIDs, review.prID)
+		}
 	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	if len(pending) == 0 {
+		if err := tx.Commit(reqCtx); err != nil {
+			logrus.Error(logPrefix, err.Error())
+			return nil, &errs.InternalError{}
+		}
+		return []domain.Reassignment{}, nil
+	}
+
+	rows, err = tx.Query(reqCtx, `SELECT pr_id, user_id FROM pr_reviewers WHERE pr_id = ANY($1)`, prIDs)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	assigned := make(map[string]map[int]bool, len(prIDs))
+	for rows.Next() {
+		var prID string
+		var id int
+		if err := rows.Scan(&prID, &id); err != nil {
+			rows.Close()
+			logrus.Error(logPrefix, err.Error())
+			return nil, &errs.InternalError{}
+		}
+		if assigned[prID] == nil {
+			assigned[prID] = make(map[int]bool)
+		}
+		assigned[prID][id] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+
+	// 3. Оставшиеся активные участники с нагрузкой; распределяем ревью в памяти
+	reviewers, candidates, err := loadCandidates(reqCtx, tx, teamName, internalIDs)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	picked := distributeReviews(settings, pending, assigned, reviewers, candidates, selectors)
+
+	// 4. Применяем все замены набором запросов
+	var (
+		oldPRs, newPRs []string
+		oldIDs, newIDs []int
+	)
+	for index, review := range pending {
+		oldPRs = append(oldPRs, review.prID)
+		oldIDs = append(oldIDs, review.oldID)
+		if picked[index] != nil {
+			newPRs = append(newPRs, review.prID)
+			newIDs = append(newIDs, picked[index].id)
+		}
+	}
+	if _, err := tx.Exec(reqCtx, `
+        DELETE FROM pr_reviewers
+        WHERE (pr_id, user_id) IN (SELECT * FROM unnest($1::text[], $2::int[]))
+    `, oldPRs, oldIDs); err != nil {
+		logrus.Error(logPrefix, "(delete reviewers) error:", err.Error())
+		return nil, &errs.InternalError{}
+	}
+	if len(newPRs) > 0 {
+		if _, err := tx.Exec(reqCtx, `
+            INSERT INTO pr_reviewers (pr_id, user_id, team_name)
+            SELECT pr_id, user_id, $3 FROM unnest($1::text[], $2::int[]) AS t(pr_id, user_id)
+        `, newPRs, newIDs, teamName); err != nil {
+			logrus.Error(logPrefix, "(insert reviewers) error:", err.Error())
+			return nil, &errs.InternalError{}
+		}
+	}
+	rows, err = tx.Query(reqCtx, `
+        UPDATE prs p
+        SET need_more_reviewers = (SELECT COUNT(*) FROM pr_reviewers WHERE pr_id = p.id) < $2,
+            updated_at = $3
+        WHERE p.id = ANY($1)
+        RETURNING p.id, p.need_more_reviewers
+    `, prIDs, settings.MinReviewers, time.Now())
+	if err != nil {
+		logrus.Error(logPrefix, "(update prs) error:", err.Error())
+		return nil, &errs.InternalError{}
+	}
+	needMore := make(map[string]bool, len(prIDs))
+	for rows.Next() {
+		var prID string
+		var flag bool
+		if err := rows.Scan(&prID, &flag); err != nil {
+			rows.Close()
+			logrus.Error(logPrefix, err.Error())
+			return nil, &errs.InternalError{}
+		}
+		needMore[prID] = flag
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+
+	reassignments := make([]domain.Reassignment, len(pending))
+	for index, review := range pending {
+		reassignments[index] = domain.Reassignment{
+			PrID:              review.prID,
+			OldReviewerID:     review.oldUser,
+			NeedMoreReviewers: needMore[review.prID],
+		}
+		if picked[index] != nil {
+			reassignments[index].NewReviewerID = picked[index].userID
+		}
+	}
+	// История пишется одним запросом, чтобы деактивация большой команды оставалась set-based
+	if err := recordReassignments(reqCtx, tx, actor.UserID, "", reassignments); err != nil {
+		logrus.Error(logPrefix, "(record events) error:", err.Error())
+		return nil, &errs.InternalError{}
+	}
+
+	if err := tx.Commit(reqCtx); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	return reassignments, nil
+}
+
+// Transfer implements domain.UserRepository.
+// В новой команде создаётся новое членство, а прежнее отвязывается от команды, как в RemoveMember:
+// PR и решения пользователя остаются за прежним членством и не попадают в статистику новой команды.
+func (u *userRepository) Transfer(fromTeam string, userID string, toTeam string, selectors *domain.ReviewerSelectors) (*domain.Transfer, error) {
+	reqCtx, cancel := context.WithTimeout(u.ctx, u.rtimeout)
+	defer cancel()
+
+	tx, err := u.pool.Begin(reqCtx)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	defer tx.Rollback(reqCtx)
 
-	return username, nil
+	// Блокируем обе команды в одном порядке, чтобы встречные переводы не взаимоблокировались
+	teams := []string{fromTeam, toTeam}
+	sort.Strings(teams)
+	for _, team := range teams {
+		if err := lockExistingTeam(reqCtx, tx, team); err != nil {
+			return nil, err
+		}
+	}
+
+	var clash bool
+	if err := tx.QueryRow(reqCtx,
+		`SELECT EXISTS (SELECT 1 FROM team_members WHERE team_name = $1 AND user_id = $2)`,
+		toTeam, userID).Scan(&clash); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	if clash {
+		return nil, &errs.DomainError{Code: codes.USER_EXISTS, Desc: "user is already a member of team '" + toTeam + "'"}
+	}
+
+	var oldID, internalID int
+	transfer := &domain.Transfer{User: domain.User{UserID: userID, TeamName: toTeam}}
+	if err := tx.QueryRow(reqCtx, `
+        UPDATE team_members m SET team_name = NULL, is_active = false
+        FROM team_members prev
+        JOIN users u ON u.user_id = prev.user_id
+        WHERE m.id = prev.id AND prev.team_name = $1 AND prev.user_id = $2
+        RETURNING m.id, u.login, u.name, prev.is_active
+    `, fromTeam, userID).Scan(&oldID, &transfer.User.Login, &transfer.User.UserName, &transfer.User.IsActive); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &errs.NotFoundError{Domain: "user"}
+		}
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	// Роль в прежней команде не переносится: в новой пользователь становится рядовым участником
+	if err := tx.QueryRow(reqCtx, `
+        INSERT INTO team_members (user_id, team_name, is_active, role, mandatory_reviewer)
+        VALUES ($1, $2, $3, 'member', false)
+        RETURNING id
+    `, userID, toTeam, transfer.User.IsActive).Scan(&internalID); err != nil {
+		logrus.Error(logPrefix, "(insert member) error: ", err.Error())
+		return nil, &errs.InternalError{}
+	}
+
+	// Ревьюверы PR должны быть из команды PR: OPEN ревью в прежней команде передаём её участникам
+	fromSettings, err := loadTeamSettings(reqCtx, tx, fromTeam)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	if transfer.Reassignments, err = releaseReviews(reqCtx, tx, fromSettings, oldID, userID, "", selectors); err != nil {
+		logrus.Error(logPrefix, "(release reviews) error:", err.Error())
+		return nil, &errs.InternalError{}
+	}
+
+	if transfer.User.IsActive {
+		toSettings, err := loadTeamSettings(reqCtx, tx, toTeam)
+		if err != nil {
+			logrus.Error(logPrefix, err.Error())
+			return nil, &errs.InternalError{}
+		}
+		if toSettings.AutoBackfill {
+			if transfer.AssignedPRs, err = backfillReviewer(reqCtx, tx, toSettings, reviewer{id: internalID, userID: userID}, ""); err != nil {
+				logrus.Error(logPrefix, "(backfill) error:", err.Error())
+				return nil, &errs.InternalError{}
+			}
+		}
+	}
+
+	if err := tx.Commit(reqCtx); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	return transfer, nil
+}
+
+// resolveMemberTeam находит команду пользователя, если он состоит ровно в одной
+func resolveMemberTeam(ctx context.Context, tx pgx.Tx, userID string) (string, error) {
+	var teams []string
+	if err := tx.QueryRow(ctx,
+		`SELECT COALESCE(array_agg(team_name ORDER BY team_name), '{}') FROM team_members WHERE user_id = $1 AND team_name IS NOT NULL`,
+		userID).Scan(&teams); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return "", &errs.InternalError{}
+	}
+	switch len(teams) {
+	case 0:
+		return "", &errs.NotFoundError{Domain: "user"}
+	case 1:
+		return teams[0], nil
+	default:
+		return "", &errs.InvalidError{
+			Domain: "user",
+			Desc:   "user belongs to several teams (" + strings.Join(teams, ", ") + "), team_name is required",
+		}
+	}
+}
+
+// Handover implements domain.UserRepository.
+// Ревью, для которых замену назначить не удалось, остаются за пользователем и попадают в результат с ошибкой.
+func (u *userRepository) Handover(userID string, teamName string, toUserID string, reason string, actor domain.Actor, selectors *domain.ReviewerSelectors) ([]domain.HandoverResult, error) {
+	reqCtx, cancel := context.WithTimeout(u.ctx, u.rtimeout)
+	defer cancel()
+
+	tx, err := u.pool.Begin(reqCtx)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	defer tx.Rollback(reqCtx)
+
+	results, err := handoverUser(reqCtx, tx, userID, teamName, toUserID, reason, actor, selectors)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(reqCtx); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	return results, nil
+}
+
+// handoverUser передаёт OPEN ревью userID во всех его командах (или только в teamName) внутри транзакции tx
+func handoverUser(ctx context.Context, tx pgx.Tx, userID, teamName, toUserID, reason string, actor domain.Actor, selectors *domain.ReviewerSelectors) ([]domain.HandoverResult, error) {
+	if toUserID != "" {
+		var exists bool
+		if err := tx.QueryRow(ctx,
+			`SELECT EXISTS (SELECT 1 FROM users WHERE user_id = $1)`, toUserID).Scan(&exists); err != nil {
+			logrus.Error(logPrefix, err.Error())
+			return nil, &errs.InternalError{}
+		}
+		if !exists {
+			return nil, &errs.NotFoundError{Domain: "user", Desc: toUserID}
+		}
+	}
+
+	// Команды обходятся по имени, чтобы встречные передачи брали lockTeam в одном порядке
+	rows, err := tx.Query(ctx, `
+        SELECT id, team_name FROM team_members
+        WHERE user_id = $1 AND team_name IS NOT NULL AND ($2 = '' OR team_name = $2)
+        ORDER BY team_name
+    `, userID, teamName)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	var memberships []reviewer
+	var teams []string
+	for rows.Next() {
+		member := reviewer{userID: userID}
+		var team string
+		if err := rows.Scan(&member.id, &team); err != nil {
+			rows.Close()
+			logrus.Error(logPrefix, err.Error())
+			return nil, &errs.InternalError{}
+		}
+		memberships = append(memberships, member)
+		teams = append(teams, team)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	if len(memberships) == 0 {
+		return nil, &errs.NotFoundError{Domain: "user"}
+	}
+
+	results := []domain.HandoverResult{}
+	for index, member := range memberships {
+		team := teams[index]
+		if err := lockTeam(ctx, tx, team); err != nil {
+			logrus.Error(logPrefix, err.Error())
+			return nil, &errs.InternalError{}
+		}
+		// При обходе всех команд архивная пропускается: её PR заморожены, а передача в остальных командах продолжается
+		if err := ensureTeamActive(ctx, tx, team); err != nil {
+			var domainErr *errs.DomainError
+			if teamName == "" && errors.As(err, &domainErr) {
+				logrus.Warn("(handover) user ", userID, " skipped in team ", team, ": ", err.Error())
+				continue
+			}
+			return nil, prLoadError(err)
+		}
+		// Свои ревью пользователь передаёт сам (по подписанному Actor-Id), чужие - лид команды
+		if !actor.Is(userID) {
+			if err := authorize(ctx, tx, team, actor, domain.TeamRole.CanManageMembers); err != nil {
+				return nil, prLoadError(err)
+			}
+		}
+		teamResults, err := handoverReviews(ctx, tx, team, member, toUserID, reason, actor.UserID, selectors)
+		if err != nil {
+			return nil, prLoadError(err)
+		}
+		results = append(results, teamResults...)
+	}
+	return results, nil
+}
+
+// handoverReviews передаёт OPEN ревью участника member в команде teamName участнику toUserID
+// или, если он пуст, по одному кандидату стратегией команды: нагрузка перечитывается после каждой замены.
+// Вызывающий должен держать lockTeam.
+func handoverReviews(ctx context.Context, tx pgx.Tx, teamName string, member reviewer, toUserID, reason, actorID string, selectors *domain.ReviewerSelectors) ([]domain.HandoverResult, error) {
+	rows, err := tx.Query(ctx, `
+        SELECT p.id, p.author_id
+        FROM pr_reviewers prr
+        JOIN prs p ON p.id = prr.pr_id
+        WHERE prr.user_id = $1 AND p.status = 'OPEN'
+        ORDER BY p.created_at, p.id
+    `, member.id)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	var (
+		prIDs     []string
+		authorIDs []int
+	)
+	for rows.Next() {
+		var prID string
+		var authorID int
+		if err := rows.Scan(&prID, &authorID); err != nil {
+			rows.Close()
+			logrus.Error(logPrefix, err.Error())
+			return nil, &errs.InternalError{}
+		}
+		prIDs = append(prIDs, prID)
+		authorIDs = append(authorIDs, authorID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+	if len(prIDs) == 0 {
+		return nil, nil
+	}
+
+	if err := ensureTeamActive(ctx, tx, teamName); err != nil {
+		return nil, err
+	}
+	settings, err := loadTeamSettings(ctx, tx, teamName)
+	if err != nil {
+		logrus.Error(logPrefix, err.Error())
+		return nil, &errs.InternalError{}
+	}
+
+	results := make([]domain.HandoverResult, 0, len(prIDs))
+	for index, prID := range prIDs {
+		result := domain.HandoverResult{PrID: prID, TeamName: teamName}
+		var candidate *reviewer
+		if toUserID != "" {
+			candidate, err = pickTarget(ctx, tx, teamName, prID, authorIDs[index], toUserID)
+			var domainErr *errs.DomainError
+			if errors.As(err, &domainErr) {
+				result.Err, err = domainErr, nil
+			}
+		} else {
+			candidate, err = pickReplacement(ctx, tx, settings, prID, authorIDs[index], selectors)
+			if err == nil && candidate == nil {
+				result.Err = &errs.DomainError{Code: codes.NO_CANDIDATE}
+			}
+		}
+		if err != nil {
+			logrus.Error(logPrefix, err.Error())
+			return nil, &errs.InternalError{}
+		}
+		if result.Err != nil {
+			results = append(results, result)
+			continue
+		}
+		if result.NeedMoreReviewers, err = swapReviewer(ctx, tx, settings, prID, member, candidate, actorID, reason); err != nil {
+			logrus.Error(logPrefix, "(swap reviewer) error:", err.Error())
+			return nil, &errs.InternalError{}
+		}
+		result.NewReviewerID = candidate.userID
+		results = append(results, result)
+	}
+	return results, nil
 }
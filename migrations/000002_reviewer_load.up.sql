@@ -0,0 +1,3 @@
+-- Подсчёт OPEN-нагрузки ревьюверов при назначении
+CREATE INDEX pr_reviewers_user_id_idx ON pr_reviewers (user_id);
+CREATE INDEX prs_status_idx ON prs (status);
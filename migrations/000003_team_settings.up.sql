@@ -0,0 +1,5 @@
+-- Настройки команды; отсутствие строки означает значения по умолчанию
+CREATE TABLE team_settings (
+  team_name text PRIMARY KEY REFERENCES teams(name) ON DELETE CASCADE,
+  reviewer_strategy text
+);
@@ -0,0 +1,5 @@
+ALTER TABLE team_settings
+  ADD COLUMN min_reviewers int NOT NULL DEFAULT 2,
+  ADD COLUMN max_reviewers int NOT NULL DEFAULT 2,
+  ADD CONSTRAINT team_settings_reviewers_check
+    CHECK (min_reviewers >= 0 AND max_reviewers >= 1 AND max_reviewers <= 10 AND min_reviewers <= max_reviewers);
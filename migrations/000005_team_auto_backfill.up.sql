@@ -0,0 +1 @@
+ALTER TABLE team_settings ADD COLUMN auto_backfill boolean NOT NULL DEFAULT false;
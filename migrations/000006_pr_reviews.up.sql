@@ -0,0 +1,13 @@
+CREATE TYPE review_state AS ENUM ('APPROVED','CHANGES_REQUESTED','COMMENTED');
+
+-- История решений ревьюверов; текущее состояние - последнее решение ревьювера по PR
+CREATE TABLE pr_reviews (
+  id serial PRIMARY KEY,
+  pr_id text NOT NULL REFERENCES prs(id) ON DELETE CASCADE,
+  user_id int NOT NULL REFERENCES users(id),
+  state review_state NOT NULL,
+  body text,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX pr_reviews_pr_user_idx ON pr_reviews (pr_id, user_id, created_at DESC);
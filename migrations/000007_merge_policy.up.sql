@@ -0,0 +1,5 @@
+-- Merge-политика команды; значения по умолчанию ничего не блокируют
+ALTER TABLE team_settings
+  ADD COLUMN min_approvals int NOT NULL DEFAULT 0 CHECK (min_approvals >= 0),
+  ADD COLUMN block_on_changes_requested boolean NOT NULL DEFAULT false,
+  ADD COLUMN block_on_need_more_reviewers boolean NOT NULL DEFAULT false;
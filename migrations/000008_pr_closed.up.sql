@@ -0,0 +1 @@
+ALTER TYPE pr_status ADD VALUE 'CLOSED';
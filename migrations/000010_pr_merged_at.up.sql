@@ -0,0 +1,4 @@
+ALTER TABLE prs ADD COLUMN merged_at timestamptz;
+
+-- Для уже смерженных PR время merge совпадало с updated_at
+UPDATE prs SET merged_at = updated_at WHERE status = 'MERGED';
@@ -0,0 +1,33 @@
+-- Команда PR фиксируется при создании и не зависит от текущей команды автора
+ALTER TABLE prs ADD COLUMN team_name text REFERENCES teams(name);
+
+UPDATE prs p SET team_name = u.team_name
+FROM users u
+WHERE u.id = p.author_id;
+
+CREATE INDEX prs_team_name_idx ON prs(team_name);
+
+-- user_id уникален в пределах команды; выбывшие участники (team_name IS NULL) не ограничиваются.
+-- Старая схема допускала дубли, а на строки ссылаются PR и ревью, поэтому они не удаляются
+-- автоматически: миграция останавливается со списком пар, которые нужно разобрать вручную
+DO $$
+DECLARE
+  duplicates text;
+BEGIN
+  SELECT string_agg(format('%s/%s (%s rows)', team_name, user_id, cnt), ', ')
+  INTO duplicates
+  FROM (
+    SELECT team_name, user_id, count(*) AS cnt
+    FROM users
+    WHERE team_name IS NOT NULL
+    GROUP BY team_name, user_id
+    HAVING count(*) > 1
+  ) d;
+  IF duplicates IS NOT NULL THEN
+    RAISE EXCEPTION 'duplicate (team_name, user_id) rows in users: %', duplicates
+      USING HINT = 'merge or remove the duplicate rows before applying this migration';
+  END IF;
+END;
+$$;
+
+CREATE UNIQUE INDEX users_team_user_id_key ON users(team_name, user_id);
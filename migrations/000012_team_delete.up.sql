@@ -0,0 +1,19 @@
+-- Архивная команда скрыта, её PR доступны только на чтение
+ALTER TABLE teams ADD COLUMN archived_at timestamptz;
+
+-- Жёсткое удаление команды каскадно удаляет её участников, PR и историю ревью
+ALTER TABLE prs
+  DROP CONSTRAINT prs_author_id_fkey,
+  ADD CONSTRAINT prs_author_id_fkey FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE,
+  DROP CONSTRAINT prs_team_name_fkey,
+  ADD CONSTRAINT prs_team_name_fkey FOREIGN KEY (team_name) REFERENCES teams(name) ON DELETE CASCADE;
+
+ALTER TABLE pr_reviewers
+  DROP CONSTRAINT pr_reviewers_user_id_fkey,
+  ADD CONSTRAINT pr_reviewers_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  DROP CONSTRAINT pr_reviewers_team_name_fkey,
+  ADD CONSTRAINT pr_reviewers_team_name_fkey FOREIGN KEY (team_name) REFERENCES teams(name) ON DELETE CASCADE;
+
+ALTER TABLE pr_reviews
+  DROP CONSTRAINT pr_reviews_user_id_fkey,
+  ADD CONSTRAINT pr_reviews_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
@@ -0,0 +1,49 @@
+-- Пользователь становится глобальным: один user_id и login на все команды.
+-- Бывшая таблица users хранит членство в командах; внутренний id сохраняется,
+-- поэтому ссылки из prs, pr_reviewers и pr_reviews остаются валидными.
+ALTER TABLE users RENAME TO team_members;
+ALTER SEQUENCE users_id_seq RENAME TO team_members_id_seq;
+ALTER INDEX users_pkey RENAME TO team_members_pkey;
+ALTER INDEX users_team_user_id_key RENAME TO team_members_team_user_id_key;
+
+CREATE TABLE users (
+  user_id text PRIMARY KEY,
+  login text NOT NULL,
+  name text NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  CONSTRAINT users_login_key UNIQUE (login)
+);
+
+-- Одинаковые user_id из разных команд сливаются в одного пользователя, только если имена совпадают.
+-- Разные имена при одном user_id могут означать разных людей: склеивать их логин и историю нельзя,
+-- поэтому миграция останавливается со списком конфликтов, которые нужно разобрать вручную
+DO $$
+DECLARE
+  conflicts text;
+BEGIN
+  SELECT string_agg(format('%s: %s', user_id, names), '; ')
+  INTO conflicts
+  FROM (
+    SELECT user_id, string_agg(DISTINCT format('%s (%s)', name, COALESCE(team_name, '-')), ', ') AS names
+    FROM team_members
+    GROUP BY user_id
+    HAVING count(DISTINCT name) > 1
+  ) c;
+  IF conflicts IS NOT NULL THEN
+    RAISE EXCEPTION 'user_id shared by members with different names: %', conflicts
+      USING HINT = 'rename the user_id of one of the members or align the names before applying this migration';
+  END IF;
+END;
+$$;
+
+INSERT INTO users (user_id, login, name)
+SELECT DISTINCT ON (user_id) user_id, user_id, name
+FROM team_members
+ORDER BY user_id, id DESC;
+
+ALTER TABLE team_members
+  ALTER COLUMN user_id TYPE text,
+  ADD CONSTRAINT team_members_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE,
+  DROP COLUMN name;
+
+CREATE INDEX team_members_user_id_idx ON team_members(user_id);
@@ -0,0 +1,7 @@
+-- Роли участников команды: лид управляет активностью участников, мейнтейнер мержит PR.
+-- Обязательным ревьювером может быть только лид.
+ALTER TABLE team_members
+  ADD COLUMN role text NOT NULL DEFAULT 'member',
+  ADD COLUMN mandatory_reviewer boolean NOT NULL DEFAULT false,
+  ADD CONSTRAINT team_members_role_check CHECK (role IN ('lead', 'maintainer', 'member')),
+  ADD CONSTRAINT team_members_mandatory_reviewer_check CHECK (NOT mandatory_reviewer OR role = 'lead');
@@ -0,0 +1,44 @@
+-- История PR: кто и когда создал, назначил, заменил ревьювера, оставил решение, смержил или закрыл
+CREATE TABLE pr_events (
+  id bigserial PRIMARY KEY,
+  pr_id text NOT NULL REFERENCES prs(id) ON DELETE CASCADE,
+  type text NOT NULL,
+  actor_id text,
+  reviewer_id text,
+  new_reviewer_id text,
+  details text,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX pr_events_pr_id_idx ON pr_events(pr_id, id);
+
+-- История только дописывается; строки удаляются лишь вместе с PR
+CREATE FUNCTION pr_events_append_only() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'pr_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER pr_events_no_update BEFORE UPDATE ON pr_events
+FOR EACH ROW EXECUTE FUNCTION pr_events_append_only();
+
+-- Восстанавливаем то, что известно о существующих PR
+INSERT INTO pr_events (pr_id, type, actor_id, created_at)
+SELECT p.id, 'created', m.user_id, p.created_at
+FROM prs p
+JOIN team_members m ON m.id = p.author_id;
+
+INSERT INTO pr_events (pr_id, type, reviewer_id, created_at)
+SELECT prr.pr_id, 'reviewer_assigned', m.user_id, prr.assigned_at
+FROM pr_reviewers prr
+JOIN team_members m ON m.id = prr.user_id
+ORDER BY prr.assigned_at;
+
+INSERT INTO pr_events (pr_id, type, reviewer_id, details, created_at)
+SELECT r.pr_id, 'review_submitted', m.user_id, r.state::text, r.created_at
+FROM pr_reviews r
+JOIN team_members m ON m.id = r.user_id
+ORDER BY r.created_at, r.id;
+
+INSERT INTO pr_events (pr_id, type, created_at)
+SELECT id, 'merged', merged_at FROM prs WHERE status = 'MERGED';
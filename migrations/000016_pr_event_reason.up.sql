@@ -0,0 +1,2 @@
+-- Причина ручного переназначения ревьювера
+ALTER TABLE pr_events ADD COLUMN reason text;
@@ -0,0 +1,19 @@
+-- Периоды недоступности пользователя (отпуск, out-of-office): [starts_at, ends_at).
+-- is_active остаётся для постоянной деактивации.
+CREATE TABLE user_availability (
+  id serial PRIMARY KEY,
+  user_id text NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+  starts_at timestamptz NOT NULL,
+  ends_at timestamptz NOT NULL,
+  reason text,
+  auto_handover boolean NOT NULL DEFAULT false,
+  handed_over_at timestamptz, -- когда OPEN ревью переданы автоматически; NULL - ещё не переданы
+  created_at timestamptz NOT NULL DEFAULT now(),
+  CONSTRAINT user_availability_period_check CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX user_availability_user_id_idx ON user_availability(user_id, starts_at);
+
+-- Периоды, для которых ещё предстоит автоматическая передача ревью
+CREATE INDEX user_availability_pending_handover_idx ON user_availability(starts_at)
+  WHERE auto_handover AND handed_over_at IS NULL;
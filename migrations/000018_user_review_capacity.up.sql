@@ -0,0 +1,4 @@
+-- Лимит одновременных OPEN ревью пользователя во всех его командах; NULL - без лимита
+ALTER TABLE users
+  ADD COLUMN max_open_reviews integer,
+  ADD CONSTRAINT users_max_open_reviews_check CHECK (max_open_reviews > 0);
@@ -0,0 +1,11 @@
+-- Статус PR до закрытия: закрытый черновик переоткрывается черновиком и получает ревьюверов только через ready
+ALTER TABLE prs ADD COLUMN closed_from pr_status;
+
+-- Для уже закрытых PR статус до закрытия не сохранился: черновиком считаем PR без назначенных ревьюверов.
+-- OPEN PR, которому не нашлось ревьюверов, тоже станет черновиком, но /pullRequest/ready назначит их заново
+UPDATE prs p
+SET closed_from = CASE
+  WHEN EXISTS (SELECT 1 FROM pr_reviewers prr WHERE prr.pr_id = p.id) THEN 'OPEN'::pr_status
+  ELSE 'DRAFT'::pr_status
+END
+WHERE p.status = 'CLOSED';
@@ -8,6 +8,13 @@ const (
 	PR_EXISTS     CODE = "PR_EXISTS"
 	INVALID_INPUT CODE = "INVALID_INPUT"
 	PR_MERGED     CODE = "PR_MERGED"
+	PR_CLOSED     CODE = "PR_CLOSED"
+	PR_DRAFT      CODE = "PR_DRAFT"
 	NOT_ASSIGNED  CODE = "NOT_ASSIGNED"
 	NO_CANDIDATE  CODE = "NO_CANDIDATE"
+	MERGE_BLOCKED CODE = "MERGE_BLOCKED"
+	TEAM_ARCHIVED CODE = "TEAM_ARCHIVED"
+	TEAM_HAS_OPEN CODE = "TEAM_HAS_OPEN_PRS"
+	USER_EXISTS   CODE = "USER_EXISTS"
+	FORBIDDEN     CODE = "FORBIDDEN"
 )
@@ -0,0 +1,32 @@
+// Package cursor кодирует позицию keyset-пагинации в непрозрачную для клиента строку.
+package cursor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+var ErrInvalid = errors.New("malformed cursor")
+
+// Encode сериализует позицию в URL-безопасную строку
+func Encode(position any) string {
+	raw, err := json.Marshal(position)
+	if err != nil {
+		// позиция - структура из простых полей, ошибка означает баг в вызывающем коде
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// Decode восстанавливает позицию из строки, полученной от Encode
+func Decode(s string, position any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return ErrInvalid
+	}
+	if err := json.Unmarshal(raw, position); err != nil {
+		return ErrInvalid
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package cursor
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type position struct {
+	Time time.Time `json:"t"`
+	ID   string    `json:"id"`
+}
+
+func TestRoundTrip(t *testing.T) {
+	want := position{Time: time.Date(2025, 1, 2, 3, 4, 5, 123456000, time.UTC), ID: "pr-1"}
+
+	var got position
+	if err := Decode(Encode(want), &got); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if !got.Time.Equal(want.Time) || got.ID != want.ID {
+		t.Fatalf("Decode() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	for _, s := range []string{"not base64!", Encode("string, not object")} {
+		var p position
+		if err := Decode(s, &p); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Decode(%q) error = %v, want ErrInvalid", s, err)
+		}
+	}
+}
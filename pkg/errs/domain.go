@@ -3,15 +3,32 @@ package errs
 import "pr-manage-service/pkg/codes"
 
 type DomainError struct {
-	Code codes.CODE
+	Code    codes.CODE
+	Desc    string   // уточнение для кодов, общих для нескольких операций
+	Details []string // например, нарушенные правила merge-политики
 }
 
 func (e *DomainError) Error() string {
+	if e.Desc != "" {
+		return e.Desc
+	}
 	switch e.Code {
 	case codes.NO_CANDIDATE:
 		return "no active replacement candidate in team"
 	case codes.PR_MERGED:
 		return "cannot reassign on merged PR"
+	case codes.PR_CLOSED:
+		return "cannot reassign on closed PR"
+	case codes.PR_DRAFT:
+		return "cannot merge draft PR, mark it ready first"
+	case codes.MERGE_BLOCKED:
+		return "merge blocked by team merge policy"
+	case codes.TEAM_ARCHIVED:
+		return "team is archived, its PRs are read-only"
+	case codes.USER_EXISTS:
+		return "user already exists"
+	case codes.TEAM_HAS_OPEN:
+		return "team has OPEN PRs, use force to delete anyway"
 	default: // codes.NOT_ASSIGNED
 		return "reviewer is not assigned to this PR"
 	}
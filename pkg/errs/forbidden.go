@@ -0,0 +1,12 @@
+package errs
+
+type ForbiddenError struct {
+	Desc string
+}
+
+func (e *ForbiddenError) Error() string {
+	if e.Desc != "" {
+		return "forbidden: " + e.Desc
+	}
+	return "forbidden"
+}